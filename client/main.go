@@ -3,11 +3,12 @@ package main
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"database/sql"
 	"time"
@@ -20,16 +21,72 @@ import (
 	pb "go-merkle-file-transfer/protos"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
-var root *merkleTree.Node
-var mt = merkleTree.NewMerkleTree()
+var root merkleTree.Digest
 
-func hashNodes(a, b []byte) []byte {
-	data := append(a, b...)
-	hash := sha256.Sum256(data)
-	return hash[:]
+// mt only tracks the right edge of the tree: the client needs the root to
+// check proofs but never generates proofs itself.
+var mt *merkleTree.CompactTree
+
+// names mirrors the server's sparse tree of file names, so that the client
+// can check the server's proofs that a file does not exist.
+var names *merkleTree.SparseMerkleTree
+
+// merkleOptions returns the tree options shared with the server. The hash
+// algorithm defaults to SHA-256 and can be changed through MERKLE_HASH, and
+// the hashing mode and tree shape are selected through MERKLE_HASH_MODE and
+// MERKLE_SHAPE.
+func merkleOptions() []merkleTree.Option {
+	alg := merkleTree.SHA256
+	if name, ok := os.LookupEnv("MERKLE_HASH"); ok {
+		alg = merkleTree.Algorithm(name)
+	}
+	newHash, ok := merkleTree.LookupAlgorithm(alg)
+	if !ok {
+		log.Fatalf("Unknown hash algorithm: %s", alg)
+	}
+	return []merkleTree.Option{
+		merkleTree.WithHash(alg, newHash),
+		merkleTree.WithHashMode(hashMode()),
+		merkleTree.WithShape(treeShape()),
+	}
+}
+
+// hashMode returns the hashing mode selected through MERKLE_HASH_MODE:
+// "rfc6962" (the default), "legacy" for trees built before domain separation,
+// or "sorted" for the sorted-pair trees of the first version.
+func hashMode() merkleTree.HashMode {
+	name, _ := os.LookupEnv("MERKLE_HASH_MODE")
+	switch name {
+	case "", "rfc6962":
+		return merkleTree.HashModeRFC6962
+	case "legacy":
+		return merkleTree.HashModeLegacy
+	case "sorted":
+		return merkleTree.HashModeSorted
+	default:
+		log.Fatalf("Unknown Merkle hash mode: %s", name)
+		return 0
+	}
+}
+
+// treeShape returns the tree shape selected through MERKLE_SHAPE: "promote"
+// (the default, format 2) or "duplicate" to keep format 1 roots.
+func treeShape() merkleTree.Shape {
+	name, _ := os.LookupEnv("MERKLE_SHAPE")
+	switch name {
+	case "", "promote":
+		return merkleTree.ShapePromote
+	case "duplicate":
+		return merkleTree.ShapeDuplicate
+	default:
+		log.Fatalf("Unknown Merkle tree shape: %s", name)
+		return 0
+	}
 }
 
 func AddLeafToDB(db *sql.DB, leafHash []byte) error {
@@ -45,13 +102,41 @@ func AddLeafToDB(db *sql.DB, leafHash []byte) error {
 	return nil
 }
 
-// RestoreTree fetches all the leaves from the database and adds them to the MerkleTree
+// RestoreTree fetches all the leaves from the database and rebuilds the MerkleTree
 func RestoreTree(db *sql.DB) error {
-	// Query the database for all leaves
-	rows, err := db.Query("SELECT leaf_content FROM merkle_leaves")
+	leaves, err := leavesFromDB(db, -1)
 	if err != nil {
 		return err
 	}
+
+	// Rebuild the trees from scratch, as they may already hold the leaves.
+	mt = merkleTree.NewCompactTree(merkleOptions()...)
+	names = merkleTree.NewSparseMerkleTree(merkleOptions()...)
+	mt.AddLeaves(leaves)
+	for _, leaf := range leaves {
+		entry, err := merkleTree.DecodeEntry(leaf)
+		if err != nil {
+			return err
+		}
+		names.Set(entry.Name, leaf)
+	}
+	return nil
+}
+
+// leavesFromDB returns the first limit leaves the client uploaded, or all of
+// them if limit is negative.
+func leavesFromDB(db *sql.DB, limit int) ([][]byte, error) {
+	// Query the database for the leaves in upload order
+	query := "SELECT leaf_content FROM merkle_leaves ORDER BY id"
+	args := []interface{}{}
+	if limit >= 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	// Read the rows and append to a slice
@@ -59,55 +144,150 @@ func RestoreTree(db *sql.DB) error {
 	for rows.Next() {
 		var leafHash []byte
 		if err := rows.Scan(&leafHash); err != nil {
-			return err
+			return nil, err
 		}
 		leaves = append(leaves, []byte(leafHash))
 	}
 
 	// Check for errors in iterating over rows.
 	if err := rows.Err(); err != nil {
-		return err
+		return nil, err
 	}
-
-	// Add leaves to MerkleTree
-	mt.AddLeaves(leaves)
-	return nil
+	return leaves, nil
 }
 
-func verifyMerkleProof(content []byte, merkleProof [][]byte, storedRoot []byte) bool {
-	// Initialize with the leaf hash
-	currentHash := sha256.Sum256(content)
-	currentBytes := currentHash[:]
+// trustedRoot returns the root the client expects the server's tree to have
+// had at size leaves: the root over the client's first size uploads, or else
+// a tree head the client accepted during an audit.
+func trustedRoot(db *sql.DB, size int) (merkleTree.Digest, error) {
+	leaves, err := leavesFromDB(db, size)
+	if err != nil {
+		return merkleTree.Digest{}, err
+	}
+	if len(leaves) == size {
+		tree := merkleTree.NewCompactTree(merkleOptions()...)
+		tree.AddLeaves(leaves)
+		return tree.RootDigest()
+	}
+
+	var rootHash []byte
+	err = db.QueryRow("SELECT root_hash FROM tree_heads WHERE tree_size = $1 ORDER BY id DESC LIMIT 1", size).Scan(&rootHash)
+	if err != nil {
+		return merkleTree.Digest{}, fmt.Errorf("no trusted root for tree size %d: %v", size, err)
+	}
+	return merkleTree.Digest{Algorithm: mt.Algorithm(), Mode: mt.Mode(), Shape: mt.Shape(), Hash: rootHash}, nil
+}
 
-	// Traverse the proof, combining and hashing at each step
-	for _, proofHash := range merkleProof {
-		// Compare current hash and proof hash to determine ordering
-		first, second := currentBytes, proofHash
-		if bytes.Compare(first, second) > 0 {
-			first, second = second, first
-		}
+// trustedHead returns the root of a tree head the server sent along with a
+// proof for a tree of proofSize leaves, once it has checked that the client
+// trusts that root. Checking proofs against the root they were generated
+// from keeps uploads landing in the meantime from failing downloads.
+func trustedHead(head *pb.TreeHead, proofSize int, db *sql.DB) (merkleTree.Digest, error) {
+	if uint64(proofSize) != head.GetTreeSize() {
+		return merkleTree.Digest{}, fmt.Errorf("Merkle proof is not for the tree head of size %d", head.GetTreeSize())
+	}
+	trusted, err := trustedRoot(db, proofSize)
+	if err != nil {
+		return merkleTree.Digest{}, err
+	}
+	if !bytes.Equal(trusted.Hash, head.GetRootHash()) {
+		return merkleTree.Digest{}, fmt.Errorf("server root for tree size %d is not the trusted one", head.GetTreeSize())
+	}
+	return trusted, nil
+}
 
-		combined := append(first, second...)
-		newHash := sha256.Sum256(combined)
-		currentBytes = newHash[:]
+// proofFromPB converts a Merkle proof received from the server.
+func proofFromPB(proof *pb.MerkleProof) *merkleTree.Proof {
+	if proof == nil {
+		return nil
+	}
+	return &merkleTree.Proof{
+		LeafIndex: int(proof.GetLeafIndex()),
+		TreeSize:  int(proof.GetTreeSize()),
+		Siblings:  proof.GetSiblings(),
+		Left:      proof.GetLeft(),
+		Mode:      merkleTree.HashMode(proof.GetHashMode()),
+		Algorithm: merkleTree.Algorithm(proof.GetAlgorithm()),
+		Shape:     merkleTree.Shape(proof.GetShape()),
 	}
-	// Compare the final calculated root with the stored root
-	return bytes.Equal(currentBytes, storedRoot)
 }
 
-func uploadFile(client pb.FileTransferClient, fileName string, content []byte, db *sql.DB) error {
+// maxUploadAttempts is how many times in a row sending a chunk may fail
+// before an upload is given up.
+const maxUploadAttempts = 5
+
+// uploadFile uploads a file in chunks through an upload session, so that its
+// size is not bounded by the gRPC message size and a dropped connection only
+// costs the chunk in flight: the upload resumes from the offset the server
+// reports.
+func uploadFile(client pb.FileTransferClient, filePath string, db *sql.DB) error {
+	fileName := getFileNameFromPath(filePath)
 	log.Printf("Uploading file: %s\n", fileName)
-	_, err := client.UploadFile(context.Background(), &pb.FileData{Name: fileName, Content: content})
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("Could not read file: %v", err)
+	}
+	defer file.Close()
+
+	// The leaf binds the file name to its content, as on the server.
+	entryWriter, err := merkleTree.NewEntryWriter(mt.Algorithm(), mt.Mode(), fileName)
 	if err != nil {
 		return err
 	}
+	if _, err := io.Copy(entryWriter, file); err != nil {
+		return fmt.Errorf("Could not read file: %v", err)
+	}
+	size := entryWriter.Size()
 
-	err = mt.AddFile(content)
+	session, err := client.StartUpload(context.Background(), &pb.UploadHeader{Name: fileName, Size: size})
 	if err != nil {
-		log.Fatalf("Failed to add file to Merkle tree: %v", err)
+		return err
 	}
+	uploadID := session.GetUploadId()
+	buf := make([]byte, merkleTree.ChunkSize)
+	for offset, failures := session.GetOffset(), 0; offset < size; {
+		n, err := file.ReadAt(buf, int64(offset))
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("Could not read file: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		progress, err := client.UploadChunk(ctx, &pb.UploadChunkRequest{
+			UploadId: uploadID,
+			Offset:   offset,
+			Chunk:    buf[:n],
+		})
+		cancel()
+		if err == nil {
+			offset, failures = progress.GetOffset(), 0
+			continue
+		}
 
-	err = AddLeafToDB(db, content)
+		failures++
+		if failures == maxUploadAttempts {
+			return fmt.Errorf("giving up after %d failed attempts: %v", failures, err)
+		}
+		log.Printf("Chunk at offset %d of %s failed (attempt %d), resuming: %v", offset, fileName, failures, err)
+		time.Sleep(time.Duration(failures) * time.Second)
+		progress, err = client.QueryUpload(context.Background(), &pb.UploadSessionID{UploadId: uploadID})
+		if status.Code(err) == codes.NotFound {
+			return err
+		}
+		if err == nil {
+			offset = progress.GetOffset()
+		}
+	}
+
+	uploadStatus, err := client.CommitUpload(context.Background(), &pb.UploadSessionID{UploadId: uploadID})
+	if err != nil {
+		return err
+	}
+	log.Printf("Uploaded %s as leaf %d of a tree of size %d", fileName, uploadStatus.GetLeafIndex(), uploadStatus.GetTreeHead().GetTreeSize())
+
+	leaf := entryWriter.Entry().Encode()
+	mt.AddFile(leaf)
+	names.Set(fileName, leaf)
+
+	err = AddLeafToDB(db, leaf)
 	if err != nil {
 		log.Fatalf("Failed to add leaf to database: %v", err)
 	}
@@ -115,50 +295,376 @@ func uploadFile(client pb.FileTransferClient, fileName string, content []byte, d
 	return nil
 }
 
-func downloadFile(client pb.FileTransferClient, fileName string, db *sql.DB) ([]byte, error) {
-	response, err := client.DownloadFile(context.Background(), &pb.FileName{Name: fileName})
+// downloadFile streams bytes [opts.offset, opts.offset + opts.length) of a
+// file from the server to outputPath, or up to the end of the file if
+// opts.length is 0, and returns how many bytes the output holds. Every chunk
+// is verified before any of it is written, and the download stops at the
+// first one that fails. The output is written under a temporary name that
+// only becomes outputPath once it is complete; with opts.resume, a temporary
+// file left by an earlier attempt is continued rather than started over.
+func downloadFile(client pb.FileTransferClient, fileName, outputPath string, opts options, db *sql.DB) (uint64, error) {
+	partPath := outputPath + ".part"
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	var done uint64
+	if opts.resume {
+		flags = os.O_RDWR | os.O_CREATE | os.O_APPEND
+		if info, err := os.Stat(partPath); err == nil {
+			done = uint64(info.Size())
+			log.Printf("Resuming %s after %d bytes", fileName, done)
+		}
+	}
+	if opts.length > 0 && done >= opts.length {
+		return done, os.Rename(partPath, outputPath)
+	}
+	start := opts.offset + done
+	length := opts.length
+	if length > 0 {
+		length -= done
+	}
+
+	stream, err := client.DownloadFileStream(context.Background(), &pb.DownloadRequest{Name: fileName, Offset: start, Length: length})
+	if err != nil {
+		return 0, err
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return 0, checkAbsence(fileName, err, db)
+	}
+
+	fileProof := proofFromPB(first.GetFileProof())
+	if fileProof == nil {
+		return 0, fmt.Errorf("server sent no Merkle proof for %s", fileName)
+	}
+	root, err = trustedHead(first.GetTreeHead(), fileProof.TreeSize, db)
+	if err != nil {
+		return 0, err
+	}
+	entry := merkleTree.Entry{
+		Name:        first.GetEntry().GetName(),
+		Size:        first.GetEntry().GetSize(),
+		ContentHash: first.GetEntry().GetContentHash(),
+	}
+	if entry.Name != fileName {
+		return 0, fmt.Errorf("server sent %q instead", entry.Name)
+	}
+	err = merkleTree.VerifyProof(entry.Encode(), fileProof, root)
+	if err != nil {
+		return 0, fmt.Errorf("Merkle proof verification failed: %v", err)
+	}
+	if start > entry.Size {
+		return 0, fmt.Errorf("offset %d is past the end of the %d byte file", start, entry.Size)
+	}
+
+	// The chunks overlapping [start, end), or the last chunk if the range
+	// is empty, as the server sends them.
+	end := entry.Size
+	if length > 0 && length < entry.Size-start {
+		end = start + length
+	}
+	numChunks := merkleTree.NumChunks(entry.Size)
+	firstChunk := int(start / merkleTree.ChunkSize)
+	if firstChunk == numChunks {
+		firstChunk--
+	}
+	lastChunk := firstChunk
+	if end > start {
+		lastChunk = int((end - 1) / merkleTree.ChunkSize)
+	}
+
+	// Only verified data is ever written, so the temporary file is kept
+	// when the download fails, for a later -resume.
+	out, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if out != nil {
+			out.Close()
+		}
+	}()
+
+	msg := first
+	for i := firstChunk; i <= lastChunk; i++ {
+		if i > firstChunk {
+			msg, err = stream.Recv()
+			if err == io.EOF {
+				return 0, fmt.Errorf("download ended before chunk %d of %d", i, numChunks)
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+		proof := &merkleTree.ChunkProof{
+			Entry: entry,
+			Chunk: proofFromPB(msg.GetChunkProof()),
+			File:  fileProof,
+		}
+		if proof.Chunk == nil || proof.Chunk.LeafIndex != i {
+			return 0, fmt.Errorf("chunk %d is missing or out of order", i)
+		}
+		chunk := msg.GetChunk()
+		err = merkleTree.VerifyChunkProof(chunk, proof, root)
+		if err != nil {
+			return 0, fmt.Errorf("Merkle proof verification failed for chunk %d: %v", i, err)
+		}
+
+		chunkStart := uint64(i) * merkleTree.ChunkSize
+		if i == firstChunk && done > 0 {
+			// The chunk also covers the end of what an earlier attempt
+			// wrote, which must be the same file.
+			overlap := chunkStart
+			if overlap < opts.offset {
+				overlap = opts.offset
+			}
+			written := make([]byte, start-overlap)
+			if _, err := out.ReadAt(written, int64(overlap-opts.offset)); err != nil {
+				return 0, err
+			}
+			if !bytes.Equal(written, chunk[overlap-chunkStart:start-chunkStart]) {
+				return 0, fmt.Errorf("%s does not hold the start of %s", partPath, fileName)
+			}
+		}
+		lo, hi := uint64(0), uint64(len(chunk))
+		if start > chunkStart {
+			lo = start - chunkStart
+		}
+		if end < chunkStart+hi {
+			hi = end - chunkStart
+		}
+		if lo < hi {
+			if _, err := out.Write(chunk[lo:hi]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		return 0, fmt.Errorf("server sent more chunks than chunk %d", lastChunk)
+	}
+
+	err = out.Close()
+	out = nil
+	if err != nil {
+		return 0, err
+	}
+	return end - opts.offset, os.Rename(partPath, outputPath)
+}
+
+// downloadFiles downloads several files in one request and verifies them
+// together against a single Merkle multiproof.
+func downloadFiles(client pb.FileTransferClient, fileNames []string, db *sql.DB) ([]*pb.FileData, error) {
+	response, err := client.DownloadFiles(context.Background(), &pb.FileNames{Names: fileNames})
 	if err != nil {
 		return nil, err
 	}
-	err = RestoreTree(db)
+	files := response.GetFiles()
+	if len(files) != len(fileNames) {
+		return nil, fmt.Errorf("requested %d files, received %d", len(fileNames), len(files))
+	}
+	var leaves [][]byte
+	for i, file := range files {
+		// Check every entry against the name that was asked for.
+		entry, err := merkleTree.NewEntry(mt.Algorithm(), mt.Mode(), fileNames[i], file.GetContent())
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, entry.Encode())
+	}
+
+	pbProof := response.GetMerkleProof()
+	proof := &merkleTree.MultiProof{
+		Algorithm: merkleTree.Algorithm(pbProof.GetAlgorithm()),
+		Mode:      merkleTree.HashMode(pbProof.GetHashMode()),
+		Shape:     merkleTree.Shape(pbProof.GetShape()),
+		TreeSize:  int(pbProof.GetTreeSize()),
+		Hashes:    pbProof.GetHashes(),
+	}
+	for _, i := range pbProof.GetLeafIndices() {
+		proof.Indices = append(proof.Indices, int(i))
+	}
+	root, err = trustedHead(response.GetTreeHead(), proof.TreeSize, db)
 	if err != nil {
-		log.Fatalf("Failed to restore Merkle tree: %v", err)
+		return nil, err
 	}
-	root, err = mt.ComputeRoot()
+	err = merkleTree.VerifyMultiProof(leaves, proof, root)
 	if err != nil {
-		log.Fatalf("Failed to compute Merkle root: %v", err)
+		return nil, fmt.Errorf("Merkle multiproof verification failed: %v", err)
+	}
+
+	return files, nil
+}
+
+// checkAbsence verifies the proof of absence attached to a NotFound error
+// returned for fileName, and returns the error to report.
+func checkAbsence(fileName string, err error, db *sql.DB) error {
+	st := status.Convert(err)
+	if st.Code() != codes.NotFound {
+		return err
 	}
-	if !verifyMerkleProof(response.Content, response.MerkleProof, root.Hash) {
-		return nil, fmt.Errorf("Merkle proof verification failed")
+	for _, detail := range st.Details() {
+		pbProof, ok := detail.(*pb.SparseProof)
+		if !ok {
+			continue
+		}
+		err = RestoreTree(db)
+		if err != nil {
+			log.Fatalf("Failed to restore Merkle tree: %v", err)
+		}
+		proof := &merkleTree.SparseProof{
+			Algorithm: merkleTree.Algorithm(pbProof.GetAlgorithm()),
+			Mode:      merkleTree.HashMode(pbProof.GetHashMode()),
+			Key:       pbProof.GetKey(),
+			Bitmap:    pbProof.GetBitmap(),
+			Siblings:  pbProof.GetSiblings(),
+		}
+		err = merkleTree.VerifySparseProof(fileName, nil, proof, names.RootDigest())
+		if err != nil {
+			return fmt.Errorf("server reported %s as missing with an invalid proof of absence: %v", fileName, err)
+		}
+		return fmt.Errorf("%s does not exist (proof of absence verified)", fileName)
 	}
+	return fmt.Errorf("%s not found, without a proof of absence: %v", fileName, err)
+}
 
-	return response.Content, nil
+// maxChunkAttempts is how many times a chunk that fails verification is
+// fetched before the download is abandoned.
+const maxChunkAttempts = 3
+
+// downloadFileChunks downloads a file one chunk at a time, verifying every
+// chunk against the Merkle root and fetching only the corrupt ones again.
+func downloadFileChunks(client pb.FileTransferClient, fileName string, db *sql.DB) ([]byte, error) {
+	var content []byte
+	// The number of chunks is only known once the first entry is verified.
+	numChunks := 1
+	for i := 0; i < numChunks; i++ {
+		chunk, entry, err := downloadChunk(client, fileName, i, db)
+		if err != nil {
+			return nil, err
+		}
+		numChunks = merkleTree.NumChunks(entry.Size)
+		content = append(content, chunk...)
+	}
+	return content, nil
 }
-func getFileNameFromPath(filePath string) string {
-	segments := strings.Split(filePath, "/")
-	return segments[len(segments)-1]
+
+// downloadChunk fetches and verifies one chunk of a file, retrying when the
+// chunk does not match its proof.
+func downloadChunk(client pb.FileTransferClient, fileName string, index int, db *sql.DB) ([]byte, merkleTree.Entry, error) {
+	var lastErr error
+	for attempt := 1; attempt <= maxChunkAttempts; attempt++ {
+		response, err := client.DownloadChunk(context.Background(), &pb.ChunkRequest{Name: fileName, Index: uint64(index)})
+		if err != nil {
+			return nil, merkleTree.Entry{}, err
+		}
+
+		proof := chunkProofFromPB(response)
+		if proof.Entry.Name != fileName {
+			lastErr = fmt.Errorf("chunk belongs to %q", proof.Entry.Name)
+		} else if proof.File == nil {
+			lastErr = fmt.Errorf("server sent no Merkle proof for %s", fileName)
+		} else if root, lastErr = trustedHead(response.GetTreeHead(), proof.File.TreeSize, db); lastErr == nil {
+			lastErr = merkleTree.VerifyChunkProof(response.GetChunk(), proof, root)
+		}
+		if lastErr == nil {
+			return response.GetChunk(), proof.Entry, nil
+		}
+		log.Printf("Chunk %d of %s failed verification (attempt %d): %v", index, fileName, attempt, lastErr)
+	}
+	return nil, merkleTree.Entry{}, fmt.Errorf("Merkle proof verification failed for chunk %d: %v", index, lastErr)
 }
 
-// Reads file from a given location and returns its content
-func getFileFromLocation(filePath string) ([]byte, error) {
+// chunkProofFromPB converts a chunk proof received from the server.
+func chunkProofFromPB(response *pb.ChunkResponse) *merkleTree.ChunkProof {
+	return &merkleTree.ChunkProof{
+		Entry: merkleTree.Entry{
+			Name:        response.GetEntry().GetName(),
+			Size:        response.GetEntry().GetSize(),
+			ContentHash: response.GetEntry().GetContentHash(),
+		},
+		Chunk: proofFromPB(response.GetChunkProof()),
+		File:  proofFromPB(response.GetFileProof()),
+	}
+}
 
-	content, err := os.ReadFile(filePath)
+// latestTreeHead returns the last tree head the client has accepted.
+func latestTreeHead(db *sql.DB) (int, []byte, error) {
+	var size int
+	var rootHash []byte
+	err := db.QueryRow("SELECT tree_size, root_hash FROM tree_heads ORDER BY id DESC LIMIT 1").Scan(&size, &rootHash)
 	if err != nil {
-		return nil, fmt.Errorf("Could not read file: %v", err)
+		return 0, nil, err
 	}
-	return content, nil
+	return size, rootHash, nil
+}
+
+// AddTreeHeadToDB records a tree head the client has accepted.
+func AddTreeHeadToDB(db *sql.DB, size int, rootHash []byte) error {
+	_, err := db.Exec("INSERT INTO tree_heads (tree_size, root_hash) VALUES ($1, $2)", size, rootHash)
+	if err != nil {
+		return fmt.Errorf("Failed to insert tree head: %v", err)
+	}
+	return nil
+}
+
+// auditServer checks that the server's current tree is an append-only
+// extension of the last tree head the client accepted, or of the tree made of
+// the client's own uploads the first time, and then accepts the new head.
+func auditServer(client pb.FileTransferClient, db *sql.DB) error {
+	oldSize, oldHash, err := latestTreeHead(db)
+	if err == sql.ErrNoRows {
+		err = RestoreTree(db)
+		if err != nil {
+			log.Fatalf("Failed to restore Merkle tree: %v", err)
+		}
+		root, err = mt.RootDigest()
+		if err != nil {
+			return fmt.Errorf("no tree head to audit from: %v", err)
+		}
+		oldSize, oldHash = mt.Size(), root.Hash
+	} else if err != nil {
+		return err
+	}
+	oldRoot := merkleTree.Digest{Algorithm: mt.Algorithm(), Mode: mt.Mode(), Shape: mt.Shape(), Hash: oldHash}
+
+	response, err := client.GetConsistencyProof(context.Background(), &pb.ConsistencyRequest{OldSize: uint64(oldSize)})
+	if err != nil {
+		return err
+	}
+	head := response.GetNewHead()
+	newRoot := merkleTree.Digest{
+		Algorithm: merkleTree.Algorithm(head.GetAlgorithm()),
+		Mode:      merkleTree.HashMode(head.GetHashMode()),
+		Shape:     merkleTree.Shape(head.GetShape()),
+		Hash:      head.GetRootHash(),
+	}
+	proof := response.GetProof()
+	if proof.GetNewSize() != head.GetTreeSize() {
+		return fmt.Errorf("consistency proof is for size %d, tree head is for size %d", proof.GetNewSize(), head.GetTreeSize())
+	}
+	err = merkleTree.VerifyConsistency(&merkleTree.ConsistencyProof{
+		Algorithm: merkleTree.Algorithm(proof.GetAlgorithm()),
+		Mode:      merkleTree.HashMode(proof.GetHashMode()),
+		Shape:     merkleTree.Shape(proof.GetShape()),
+		OldSize:   int(proof.GetOldSize()),
+		NewSize:   int(proof.GetNewSize()),
+		Hashes:    proof.GetHashes(),
+	}, oldRoot, newRoot)
+	if err != nil {
+		return fmt.Errorf("server history was rewritten since size %d: %v", oldSize, err)
+	}
+
+	log.Printf("Tree at size %d is consistent with size %d", head.GetTreeSize(), oldSize)
+	return AddTreeHeadToDB(db, int(head.GetTreeSize()), head.GetRootHash())
+}
+
+func getFileNameFromPath(filePath string) string {
+	segments := strings.Split(filePath, "/")
+	return segments[len(segments)-1]
 }
 
 // Uploads multiple files based on a list of file paths
 func uploadFiles(client pb.FileTransferClient, filePaths []string, db *sql.DB) {
 	for _, filePath := range filePaths {
-		content, err := getFileFromLocation(filePath)
-		if err != nil {
-			log.Printf("Could not read file %s: %v", filePath, err)
-			continue // Skip to the next file
-		}
-		fileName := getFileNameFromPath(filePath)
-		err = uploadFile(client, fileName, content, db)
+		err := uploadFile(client, filePath, db)
 		if err != nil {
 			log.Printf("Upload failed for file %s: %v", filePath, err)
 		}
@@ -187,33 +693,175 @@ func initGRPCClient(serverAddr string) *grpc.ClientConn {
 	log.Fatalf("Failed to connect after %d attempts", maxRetries)
 	return nil
 }
-func handleOperation(operation string, filePathList []string, client pb.FileTransferClient, db *sql.DB) {
+
+// dumpTree writes the server's tree to stdout, with the proof path of the
+// named file highlighted unless name is empty.
+func dumpTree(client pb.FileTransferClient, name string, opts options) error {
+	format := pb.DumpFormat_DUMP_FORMAT_JSON
+	switch opts.format {
+	case "json":
+	case "dot":
+		format = pb.DumpFormat_DUMP_FORMAT_DOT
+	default:
+		return fmt.Errorf("unknown dump format: %s", opts.format)
+	}
+
+	dump, err := client.DumpTree(context.Background(), &pb.DumpTreeRequest{
+		Name:     name,
+		TreeSize: opts.treeSize,
+		Format:   format,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(dump.GetData())
+	return err
+}
+
+// listFiles prints one page of the files stored on the server whose names
+// start with opts.prefix, and the token of the next page if there is one.
+func listFiles(client pb.FileTransferClient, opts options) error {
+	orders := map[string]pb.ListOrder{
+		"name": pb.ListOrder_LIST_ORDER_NAME,
+		"time": pb.ListOrder_LIST_ORDER_UPLOAD_TIME,
+		"size": pb.ListOrder_LIST_ORDER_SIZE,
+	}
+	order, ok := orders[opts.order]
+	if !ok {
+		return fmt.Errorf("unknown list order: %s", opts.order)
+	}
+
+	page, err := client.ListFiles(context.Background(), &pb.ListFilesRequest{
+		Prefix:     opts.prefix,
+		PageSize:   uint32(opts.pageSize),
+		PageToken:  opts.pageToken,
+		Order:      order,
+		Descending: opts.descending,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tUPLOADED\tLEAF\tHASH")
+	for _, file := range page.GetFiles() {
+		leaf := "-"
+		if file.GetLeafIndex() >= 0 {
+			leaf = fmt.Sprint(file.GetLeafIndex())
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%x\n", file.GetName(), file.GetSize(),
+			time.Unix(file.GetUploadTime(), 0).UTC().Format(time.RFC3339), leaf, file.GetContentHash())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if page.GetNextPageToken() != "" {
+		log.Printf("More files follow: -pageToken=%s", page.GetNextPageToken())
+	}
+	return nil
+}
+
+// options holds the flags that only some operations use.
+type options struct {
+	format   string
+	treeSize uint64
+	output   string
+	offset   uint64
+	length   uint64
+	resume   bool
+
+	prefix     string
+	pageSize   uint
+	pageToken  string
+	order      string
+	descending bool
+}
+
+func handleOperation(operation string, filePathList []string, opts options, client pb.FileTransferClient, db *sql.DB) {
 	switch operation {
 	case "upload":
 		uploadFiles(client, filePathList, db)
 	case "download":
 		fileName := getFileNameFromPath(filePathList[0])
-		content, err := downloadFile(client, fileName, db)
+		outputPath := opts.output
+		if outputPath == "" {
+			outputPath = fileName
+		}
+		size, err := downloadFile(client, fileName, outputPath, opts, db)
 		if err != nil {
 			log.Fatalf("Download failed: %v", err)
 		}
-		log.Printf("Downloaded content: %s", string(content))
+		log.Printf("Downloaded %s to %s (%d bytes)", fileName, outputPath, size)
+	case "download-chunks":
+		fileName := getFileNameFromPath(filePathList[0])
+		content, err := downloadFileChunks(client, fileName, db)
+		if err != nil {
+			log.Fatalf("Download failed: %v", err)
+		}
+		log.Printf("Downloaded %d bytes in %d chunks", len(content), merkleTree.NumChunks(uint64(len(content))))
+	case "download-batch":
+		var fileNames []string
+		for _, filePath := range filePathList {
+			fileNames = append(fileNames, getFileNameFromPath(filePath))
+		}
+		files, err := downloadFiles(client, fileNames, db)
+		if err != nil {
+			log.Fatalf("Download failed: %v", err)
+		}
+		for _, file := range files {
+			log.Printf("Downloaded %s: %s", file.GetName(), string(file.GetContent()))
+		}
+	case "audit":
+		err := auditServer(client, db)
+		if err != nil {
+			log.Fatalf("Audit failed: %v", err)
+		}
+	case "dump":
+		var fileName string
+		if filePathList[0] != "" {
+			fileName = getFileNameFromPath(filePathList[0])
+		}
+		if err := dumpTree(client, fileName, opts); err != nil {
+			log.Fatalf("Dump failed: %v", err)
+		}
+	case "list":
+		if err := listFiles(client, opts); err != nil {
+			log.Fatalf("List failed: %v", err)
+		}
 	default:
 		log.Fatalf("Invalid operation: %s", operation)
 	}
 }
 
 func main() {
+	mt = merkleTree.NewCompactTree(merkleOptions()...)
+	names = merkleTree.NewSparseMerkleTree(merkleOptions()...)
+
 	connStr := "host=client-db port=5432 user=clientuser password=clientpassword dbname=clientdb sslmode=disable"
 	db := initDB(connStr)
 	defer db.Close()
 
-	operation := flag.String("operation", "", "Operation to perform: upload or download")
+	operation := flag.String("operation", "", "Operation to perform: upload, download, download-batch, download-chunks, audit, dump or list")
 	filePaths := flag.String("filePaths", "", "Comma-separated list of paths to the files to upload")
+	var opts options
+	flag.StringVar(&opts.format, "format", "json", "Format of the tree dump: json or dot")
+	flag.Uint64Var(&opts.treeSize, "treeSize", 0, "Size of the tree version to dump, 0 for the current one")
+	flag.StringVar(&opts.output, "output", "", "Path to write a downloaded file to, by default its name in the current directory")
+	flag.Uint64Var(&opts.offset, "offset", 0, "First byte of the file to download")
+	flag.Uint64Var(&opts.length, "length", 0, "Number of bytes to download, 0 for the rest of the file")
+	flag.BoolVar(&opts.resume, "resume", false, "Continue the partial output of an interrupted download")
+	flag.StringVar(&opts.prefix, "prefix", "", "Only list files whose name starts with this prefix")
+	flag.UintVar(&opts.pageSize, "pageSize", 0, "Number of files to list, 0 for the server's default")
+	flag.StringVar(&opts.pageToken, "pageToken", "", "Token of the page to list, as printed after the previous page")
+	flag.StringVar(&opts.order, "order", "name", "Order to list files in: name, time or size")
+	flag.BoolVar(&opts.descending, "desc", false, "List files in descending order")
 	flag.Parse()
 
-	if *operation == "" || *filePaths == "" {
-		log.Fatalf("Both 'operation' and 'filePaths' must be specified.")
+	if *operation == "" {
+		log.Fatalf("'operation' must be specified.")
+	}
+	if *filePaths == "" && *operation != "audit" && *operation != "dump" && *operation != "list" {
+		log.Fatalf("'filePaths' must be specified for %s.", *operation)
 	}
 
 	filePathList := strings.Split(*filePaths, ",")
@@ -226,5 +874,5 @@ func main() {
 	defer conn.Close()
 
 	client := pb.NewFileTransferClient(conn)
-	handleOperation(*operation, filePathList, client, db)
+	handleOperation(*operation, filePathList, opts, client, db)
 }
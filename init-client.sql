@@ -1,4 +1,9 @@
 CREATE TABLE IF NOT EXISTS merkle_leaves (
     id SERIAL PRIMARY KEY,
     leaf_content BYTEA NOT NULL
+);
+CREATE TABLE IF NOT EXISTS tree_heads (
+    id SERIAL PRIMARY KEY,
+    tree_size BIGINT NOT NULL,
+    root_hash BYTEA NOT NULL
 );
\ No newline at end of file
@@ -1,5 +1,30 @@
 CREATE TABLE IF NOT EXISTS file_storage (
   file_name VARCHAR(255) PRIMARY KEY,
-  file_content BYTEA NOT NULL UNIQUE,
-  merkle_root BYTEA
+  file_content BYTEA NOT NULL, -- not UNIQUE: B-tree index entries cannot hold large files
+  merkle_root BYTEA,
+  uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  chunk_hashes BYTEA -- leaf hashes of the file's chunks, concatenated
+);
+-- Files are stored uncompressed, so that reading a piece of one with
+-- substring only fetches the TOAST chunks it covers. A compressed file would
+-- be decompressed up to the piece on every read.
+ALTER TABLE file_storage ALTER COLUMN file_content SET STORAGE EXTERNAL;
+-- Databases created by earlier versions are brought up to date here. Their
+-- files get the migration time as upload time, and are hashed chunk by chunk
+-- on their first download.
+ALTER TABLE file_storage ADD COLUMN IF NOT EXISTS uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE file_storage ADD COLUMN IF NOT EXISTS chunk_hashes BYTEA;
+ALTER TABLE file_storage DROP CONSTRAINT IF EXISTS file_storage_file_content_key;
+CREATE TABLE IF NOT EXISTS upload_chunks (
+  upload_id VARCHAR(64) NOT NULL,
+  chunk_offset BIGINT NOT NULL,
+  chunk_content BYTEA NOT NULL,
+  PRIMARY KEY (upload_id, chunk_offset)
+);
+CREATE TABLE IF NOT EXISTS upload_sessions (
+  upload_id VARCHAR(64) PRIMARY KEY,
+  file_name VARCHAR(255) NOT NULL,
+  file_size BIGINT NOT NULL,
+  received BIGINT NOT NULL DEFAULT 0,
+  expires_at TIMESTAMPTZ NOT NULL
 );
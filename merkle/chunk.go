@@ -0,0 +1,179 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ChunkSize is the size of the chunks a file is split into. Every chunk but
+// the last one is exactly ChunkSize bytes long.
+const ChunkSize = 1 << 20
+
+// NumChunks returns the number of chunks a file of the given size is split
+// into. An empty file is a single empty chunk.
+func NumChunks(size uint64) int {
+	if size == 0 {
+		return 1
+	}
+	return int((size + ChunkSize - 1) / ChunkSize)
+}
+
+// ChunkTree is the Merkle tree over the chunks of a single file. Its root is
+// the content hash committed to by the file's Entry, so every chunk can be
+// verified on its own against the file's leaf in the global tree.
+type ChunkTree struct {
+	tree *MerkleTree
+	size uint64
+}
+
+// NewChunkTree reads a file from r and builds the tree over its chunks. Only
+// the chunk hashes are kept in memory.
+func NewChunkTree(r io.Reader, opts ...Option) (*ChunkTree, error) {
+	// Chunk trees are never proven against past versions, and keep the
+	// format 1 shape: the entry fixes the number of chunks, so the shape
+	// cannot hide a phantom chunk.
+	opts = append(opts[:len(opts):len(opts)], WithSnapshots(0), WithShape(ShapeDuplicate))
+	ct := &ChunkTree{tree: NewMerkleTree(opts...)}
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 || ct.size == 0 && err == io.EOF {
+			if err := ct.tree.AddFile(buf[:n]); err != nil {
+				return nil, err
+			}
+			ct.size += uint64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ct, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read chunk %d: %v", len(ct.tree.Leaves), err)
+		}
+	}
+}
+
+// NewChunkTreeFromHashes rebuilds the chunk tree of a file of the given size
+// from the leaf hashes of its chunks, as returned by ChunkHashes, without
+// reading the file.
+func NewChunkTreeFromHashes(size uint64, hashes [][]byte, opts ...Option) (*ChunkTree, error) {
+	if len(hashes) != NumChunks(size) {
+		return nil, fmt.Errorf("%d chunk hashes for a file of %d chunks", len(hashes), NumChunks(size))
+	}
+	h := NewMerkleTree(opts...).hasher
+	tree, err := buildFromState(TreeState{
+		Version:   TreeStateVersion,
+		Algorithm: h.alg,
+		Mode:      h.mode,
+		Shape:     ShapeDuplicate,
+		Leaves:    hashes,
+	}, append(opts[:len(opts):len(opts)], WithSnapshots(0))...)
+	if err != nil {
+		return nil, err
+	}
+	return &ChunkTree{tree: tree, size: size}, nil
+}
+
+// ChunkHashes returns the leaf hashes of the chunks, in order.
+func (ct *ChunkTree) ChunkHashes() [][]byte {
+	return ct.tree.State().Leaves
+}
+
+// Size returns the size of the file in bytes.
+func (ct *ChunkTree) Size() uint64 {
+	return ct.size
+}
+
+// NumChunks returns the number of chunks in the file.
+func (ct *ChunkTree) NumChunks() int {
+	return len(ct.tree.Leaves)
+}
+
+// RootDigest returns the root of the chunk tree.
+func (ct *ChunkTree) RootDigest() (Digest, error) {
+	return ct.tree.RootDigest()
+}
+
+// Entry returns the entry of the file under the given name.
+func (ct *ChunkTree) Entry(name string) Entry {
+	root, _ := ct.RootDigest()
+	return Entry{Name: name, Size: ct.size, ContentHash: root.Hash}
+}
+
+// GenerateProof returns the inclusion proof of a chunk in the file.
+func (ct *ChunkTree) GenerateProof(chunkIndex int) (*Proof, error) {
+	return ct.tree.GenerateProof(chunkIndex)
+}
+
+// chunkRoot returns the root of the chunk tree of content.
+func (h hasher) chunkRoot(content []byte) []byte {
+	ct := &CompactTree{hasher: h}
+	for offset := 0; offset < len(content) || ct.size == 0; offset += ChunkSize {
+		end := offset + ChunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		ct.appendHash(h.leaf(content[offset:end]))
+	}
+	root, _ := ct.RootDigest()
+	return root.Hash
+}
+
+// ChunkProof proves one chunk of a file against the root of the global tree.
+type ChunkProof struct {
+	// Entry is the file the chunk belongs to.
+	Entry Entry
+	// Chunk is the chunk's proof against Entry.ContentHash.
+	Chunk *Proof
+	// File is the entry's proof against the global root.
+	File *Proof
+}
+
+// VerifyChunkProof checks that chunk is chunk number proof.Chunk.LeafIndex of
+// the file described by proof.Entry, and that the file is in the tree whose
+// root is root. Callers should also check proof.Entry.Name.
+func VerifyChunkProof(chunk []byte, proof *ChunkProof, root Digest) error {
+	if proof == nil || proof.Chunk == nil {
+		return errors.New("missing proof")
+	}
+	if err := VerifyProof(proof.Entry.Encode(), proof.File, root); err != nil {
+		return fmt.Errorf("file entry: %v", err)
+	}
+
+	size := proof.Entry.Size
+	if proof.Chunk.TreeSize != NumChunks(size) {
+		return fmt.Errorf("chunk proof is for %d chunks, file has %d", proof.Chunk.TreeSize, NumChunks(size))
+	}
+	expected := uint64(ChunkSize)
+	if proof.Chunk.LeafIndex == proof.Chunk.TreeSize-1 {
+		expected = size - uint64(proof.Chunk.LeafIndex)*ChunkSize
+	}
+	if uint64(len(chunk)) != expected {
+		return fmt.Errorf("chunk %d has %d bytes, expected %d", proof.Chunk.LeafIndex, len(chunk), expected)
+	}
+
+	fileRoot := Digest{
+		Algorithm: root.Algorithm,
+		Mode:      root.Mode,
+		Shape:     ShapeDuplicate,
+		Hash:      proof.Entry.ContentHash,
+	}
+	if err := VerifyProof(chunk, proof.Chunk, fileRoot); err != nil {
+		return fmt.Errorf("chunk %d: %v", proof.Chunk.LeafIndex, err)
+	}
+	return nil
+}
+
+// Chunk returns chunk number i of content.
+func Chunk(content []byte, i int) ([]byte, error) {
+	if i < 0 || i >= NumChunks(uint64(len(content))) {
+		return nil, errors.New("invalid chunk index")
+	}
+	start := i * ChunkSize
+	end := start + ChunkSize
+	if end > len(content) {
+		end = len(content)
+	}
+	return bytes.Clone(content[start:end]), nil
+}
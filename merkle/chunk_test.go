@@ -0,0 +1,91 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNumChunks(t *testing.T) {
+	for _, tc := range []struct {
+		size uint64
+		want int
+	}{
+		{0, 1},
+		{1, 1},
+		{ChunkSize, 1},
+		{ChunkSize + 1, 2},
+		{3 * ChunkSize, 3},
+	} {
+		if got := NumChunks(tc.size); got != tc.want {
+			t.Errorf("NumChunks(%d) = %d, want %d", tc.size, got, tc.want)
+		}
+	}
+}
+
+func TestChunkTreeMatchesEntry(t *testing.T) {
+	for _, size := range []int{0, 10, ChunkSize, 2*ChunkSize + 5} {
+		content := bytes.Repeat([]byte{'x'}, size)
+		ct, err := NewChunkTree(bytes.NewReader(content), WithHashMode(HashModeRFC6962))
+		if err != nil {
+			t.Fatalf("Failed to build chunk tree: %v", err)
+		}
+		if ct.Size() != uint64(size) || ct.NumChunks() != NumChunks(uint64(size)) {
+			t.Errorf("Chunk tree of %d bytes has size %d and %d chunks", size, ct.Size(), ct.NumChunks())
+		}
+
+		root, _ := ct.RootDigest()
+		entry, _ := NewEntry(SHA256, HashModeRFC6962, "file", content)
+		if !bytes.Equal(root.Hash, entry.ContentHash) {
+			t.Errorf("Chunk tree root should be the entry content hash for %d bytes", size)
+		}
+		if !bytes.Equal(ct.Entry("file").Encode(), entry.Encode()) {
+			t.Errorf("Chunk tree entry differs from NewEntry for %d bytes", size)
+		}
+	}
+}
+
+func TestVerifyChunkProof(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), (2*ChunkSize+ChunkSize/2)/10)
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddEntry("other.bin", []byte("other"))
+	mt.AddEntry("large.bin", content)
+	root, _ := mt.RootDigest()
+
+	ct, err := NewChunkTree(bytes.NewReader(content), WithHashMode(HashModeRFC6962))
+	if err != nil {
+		t.Fatalf("Failed to build chunk tree: %v", err)
+	}
+	entry, _ := NewEntry(SHA256, HashModeRFC6962, "large.bin", content)
+	fileProof, _ := mt.GenerateProof(1)
+
+	for i := 0; i < ct.NumChunks(); i++ {
+		chunk, err := Chunk(content, i)
+		if err != nil {
+			t.Fatalf("Failed to read chunk: %v", err)
+		}
+		chunkProof, err := ct.GenerateProof(i)
+		if err != nil {
+			t.Fatalf("Failed to generate chunk proof: %v", err)
+		}
+		proof := &ChunkProof{Entry: entry, Chunk: chunkProof, File: fileProof}
+		if err := VerifyChunkProof(chunk, proof, root); err != nil {
+			t.Errorf("Chunk %d did not verify: %v", i, err)
+		}
+
+		corrupt := bytes.Clone(chunk)
+		corrupt[0] ^= 0xff
+		if err := VerifyChunkProof(corrupt, proof, root); err == nil {
+			t.Errorf("Corrupt chunk %d should not verify", i)
+		}
+		if err := VerifyChunkProof(chunk[:len(chunk)-1], proof, root); err == nil {
+			t.Errorf("Truncated chunk %d should not verify", i)
+		}
+	}
+
+	chunk, _ := Chunk(content, 0)
+	chunkProof, _ := ct.GenerateProof(0)
+	wrongFile, _ := mt.GenerateProof(0)
+	if err := VerifyChunkProof(chunk, &ChunkProof{Entry: entry, Chunk: chunkProof, File: wrongFile}, root); err == nil {
+		t.Error("Chunk should not verify with another file's proof")
+	}
+}
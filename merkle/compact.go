@@ -0,0 +1,108 @@
+package merkle
+
+import "errors"
+
+// CompactTree is an append-only Merkle accumulator that keeps only the right
+// edge of the tree: the roots of the perfect subtrees that make up its leaves,
+// one per set bit of the leaf count. Appends and roots cost O(log n) hashes
+// and the roots match a MerkleTree built from the same leaves and options, or
+// a MountainRange when the shape is ShapePromote. It cannot produce proofs;
+// use it where only the root is needed.
+type CompactTree struct {
+	hasher hasher
+	shape  Shape
+	size   int
+	// frontier[l] is the root of the perfect subtree of 2^l leaves that
+	// ends the tree, or nil when bit l of size is clear.
+	frontier [][]byte
+}
+
+// NewCompactTree creates an empty CompactTree.
+func NewCompactTree(opts ...Option) *CompactTree {
+	c := newConfig(opts)
+	return &CompactTree{hasher: c.hasher, shape: c.shape}
+}
+
+// Size returns the number of leaves appended so far.
+func (ct *CompactTree) Size() int {
+	return ct.size
+}
+
+// AddFile appends a file as a new leaf.
+func (ct *CompactTree) AddFile(fileContent []byte) {
+	ct.appendHash(ct.hasher.leaf(fileContent))
+}
+
+// AddLeaves appends several leaves in order.
+func (ct *CompactTree) AddLeaves(leaves [][]byte) {
+	for _, leaf := range leaves {
+		ct.AddFile(leaf)
+	}
+}
+
+// appendHash merges a new leaf hash into the frontier like a binary counter
+// increment: each full subtree it completes is folded into the next level.
+func (ct *CompactTree) appendHash(hash []byte) {
+	l := 0
+	for ; ct.size>>l&1 == 1; l++ {
+		hash = ct.hasher.children(ct.frontier[l], hash)
+		ct.frontier[l] = nil
+	}
+	if l == len(ct.frontier) {
+		ct.frontier = append(ct.frontier, nil)
+	}
+	ct.frontier[l] = hash
+	ct.size++
+}
+
+// RootDigest returns the Merkle root of the leaves appended so far.
+func (ct *CompactTree) RootDigest() (Digest, error) {
+	if ct.size == 0 {
+		return Digest{}, errors.New("merkle tree has not been calculated yet")
+	}
+
+	// Start from the smallest subtree, which holds the last node of its
+	// level, and climb. A level with an even number of nodes pairs that node
+	// with the full subtree to its left; an odd one duplicates or promotes
+	// it, depending on the shape.
+	l := 0
+	for ct.frontier[l] == nil {
+		l++
+	}
+	root := ct.frontier[l]
+	for ; width(ct.size, l) > 1; l++ {
+		if width(ct.size, l)%2 == 0 {
+			root = ct.hasher.children(ct.frontier[l], root)
+		} else {
+			root = ct.hasher.unpaired(root, ct.shape)
+		}
+	}
+
+	return Digest{
+		Algorithm: ct.hasher.alg,
+		Mode:      ct.hasher.mode,
+		Shape:     ct.shape,
+		Hash:      root,
+	}, nil
+}
+
+// width returns the number of nodes at the given level of a tree of size
+// leaves.
+func width(size, level int) int {
+	return (size + 1<<level - 1) >> level
+}
+
+// Algorithm returns the hash algorithm the tree was built with.
+func (ct *CompactTree) Algorithm() Algorithm {
+	return ct.hasher.alg
+}
+
+// Mode returns the hashing mode the tree was built with.
+func (ct *CompactTree) Mode() HashMode {
+	return ct.hasher.mode
+}
+
+// Shape returns the shape of the tree.
+func (ct *CompactTree) Shape() Shape {
+	return ct.shape
+}
@@ -0,0 +1,36 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompactTreeMatchesMerkleTree(t *testing.T) {
+	for _, mode := range []HashMode{HashModeLegacy, HashModeRFC6962} {
+		ct := NewCompactTree(WithHashMode(mode))
+		var leaves [][]byte
+		for size := 1; size <= 40; size++ {
+			leaf := []byte{byte(size)}
+			leaves = append(leaves, leaf)
+			ct.AddFile(leaf)
+
+			mt := NewMerkleTree(WithHashMode(mode))
+			mt.AddLeaves(leaves)
+			want, _ := mt.RootDigest()
+			got, err := ct.RootDigest()
+			if err != nil {
+				t.Fatalf("Failed to compute root: %v", err)
+			}
+			if ct.Size() != size || !bytes.Equal(got.Hash, want.Hash) {
+				t.Errorf("Compact root differs from batch root at size %d", size)
+			}
+		}
+	}
+}
+
+func TestCompactTreeEmpty(t *testing.T) {
+	ct := NewCompactTree()
+	if _, err := ct.RootDigest(); err == nil {
+		t.Error("Empty tree should not have a root")
+	}
+}
@@ -0,0 +1,122 @@
+package merkle
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// These tests are meant to be run with -race.
+
+func TestMerkleTreeConcurrentAccess(t *testing.T) {
+	const writers, readers, perWriter = 4, 8, 50
+
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddEntry("seed", []byte("seed"))
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				name := fmt.Sprintf("file-%d-%d", w, i)
+				if err := mt.AddEntry(name, []byte(name)); err != nil {
+					t.Errorf("Failed to add %s: %v", name, err)
+				}
+			}
+		}(w)
+	}
+
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				// A proof must always match the root read with it.
+				proof, root, err := mt.GenerateProofWithRoot(0)
+				if err != nil {
+					t.Errorf("Failed to generate proof: %v", err)
+					return
+				}
+				if err := VerifyEntryProof("seed", []byte("seed"), proof, root); err != nil {
+					t.Errorf("Proof for tree size %d did not verify: %v", proof.TreeSize, err)
+				}
+
+				multi, root, err := mt.GenerateMultiProofWithRoot([]int{0})
+				if err != nil {
+					t.Errorf("Failed to generate multiproof: %v", err)
+					return
+				}
+				entry, _ := NewEntry(root.Algorithm, root.Mode, "seed", []byte("seed"))
+				if err := VerifyMultiProof([][]byte{entry.Encode()}, multi, root); err != nil {
+					t.Errorf("Multiproof for tree size %d did not verify: %v", multi.TreeSize, err)
+				}
+
+				// A proof also matches the root of its own tree size.
+				proof, err = mt.GenerateProof(0)
+				if err != nil {
+					t.Errorf("Failed to generate proof: %v", err)
+					return
+				}
+				root, err = mt.RootAt(proof.TreeSize)
+				if err != nil {
+					t.Errorf("Failed to compute root: %v", err)
+					return
+				}
+				if err := VerifyEntryProof("seed", []byte("seed"), proof, root); err != nil {
+					t.Errorf("Proof for tree size %d did not verify: %v", proof.TreeSize, err)
+				}
+
+				if size := mt.Size(); size > 1 {
+					if _, err := mt.ConsistencyProof(1, size); err != nil {
+						t.Errorf("Failed to generate consistency proof: %v", err)
+					}
+				}
+				mt.GetIndicesFromEntry("seed", []byte("seed"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if size := mt.Size(); size != 1+writers*perWriter {
+		t.Errorf("Expected %d leaves, got %d", 1+writers*perWriter, size)
+	}
+	// Rebuilding from the leaves checks that every node is up to date.
+	if _, err := NewMerkleTreeFromState(mt.State()); err != nil {
+		t.Errorf("Concurrent appends left the tree inconsistent: %v", err)
+	}
+}
+
+func TestSparseMerkleTreeConcurrentAccess(t *testing.T) {
+	const writers, perWriter = 4, 25
+
+	smt := NewSparseMerkleTree(WithHashMode(HashModeRFC6962))
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				name := fmt.Sprintf("file-%d-%d", w, i)
+				smt.Set(name, []byte(name))
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				smt.GenerateProof("missing")
+				smt.RootDigest()
+				smt.Get("missing")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if smt.Len() != writers*perWriter {
+		t.Errorf("Expected %d names, got %d", writers*perWriter, smt.Len())
+	}
+	if err := VerifySparseProof("missing", nil, smt.GenerateProof("missing"), smt.RootDigest()); err != nil {
+		t.Errorf("Absence proof did not verify: %v", err)
+	}
+}
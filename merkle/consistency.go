@@ -0,0 +1,186 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ConsistencyProof proves that a tree of NewSize leaves is an append-only
+// extension of the tree made of its first OldSize leaves.
+type ConsistencyProof struct {
+	Algorithm Algorithm
+	Mode      HashMode
+	Shape     Shape
+	OldSize   int
+	NewSize   int
+	// Hashes holds the subtree hashes in the order produced by the
+	// SUBPROOF algorithm of RFC 6962, section 2.1.2.
+	Hashes [][]byte
+}
+
+// ConsistencyProof returns the proof that the tree of newSize leaves extends
+// the tree of oldSize leaves. Both trees are taken from the current leaves,
+// so a leaf updated or removed since oldSize makes the proof fail against the
+// root published back then.
+//
+// The algorithm is RFC 6962's, with subtree heights made explicit: where a
+// level has no right half, the node is promoted or duplicated as in the tree
+// itself.
+func (mt *MerkleTree) ConsistencyProof(oldSize, newSize int) (*ConsistencyProof, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	if newSize <= 0 || newSize > len(mt.Leaves) {
+		return nil, fmt.Errorf("invalid tree size %d", newSize)
+	}
+	if oldSize <= 0 || oldSize > newSize {
+		return nil, fmt.Errorf("invalid old tree size %d", oldSize)
+	}
+
+	return &ConsistencyProof{
+		Algorithm: mt.hasher.alg,
+		Mode:      mt.hasher.mode,
+		Shape:     mt.shape,
+		OldSize:   oldSize,
+		NewSize:   newSize,
+		Hashes:    mt.subproof(oldSize, 0, newSize, height(newSize), true, nil),
+	}, nil
+}
+
+// subproof appends to proof the hashes needed to rebuild both the node over
+// the first m and over all n leaves starting at start, at height h. complete
+// is true while that first node is the old root itself.
+func (mt *MerkleTree) subproof(m, start, n, h int, complete bool, proof [][]byte) [][]byte {
+	if m == n {
+		if complete {
+			return proof
+		}
+		return append(proof, mt.subtreeHash(start, n, h))
+	}
+
+	k := 1 << (h - 1)
+	if n <= k {
+		return mt.subproof(m, start, n, h-1, complete, proof)
+	}
+	if m <= k {
+		proof = mt.subproof(m, start, k, h-1, complete, proof)
+		return append(proof, mt.subtreeHash(start+k, n-k, h-1))
+	}
+	proof = mt.subproof(m-k, start+k, n-k, h-1, false, proof)
+	return append(proof, mt.subtreeHash(start, k, h-1))
+}
+
+// VerifyConsistency checks that the tree with root newRoot is an append-only
+// extension of the tree with root oldRoot.
+func VerifyConsistency(proof *ConsistencyProof, oldRoot, newRoot Digest) error {
+	if proof == nil {
+		return errors.New("missing proof")
+	}
+	for _, root := range []Digest{oldRoot, newRoot} {
+		if proof.Algorithm != root.Algorithm {
+			return fmt.Errorf("proof uses hash algorithm %q, root uses %q", proof.Algorithm, root.Algorithm)
+		}
+		if proof.Mode != root.Mode {
+			return fmt.Errorf("proof uses hash mode %v, root uses %v", proof.Mode, root.Mode)
+		}
+		if proof.Shape != root.Shape {
+			return fmt.Errorf("proof is for tree shape %v, root is for %v", proof.Shape, root.Shape)
+		}
+	}
+	if !proof.Shape.valid() {
+		return fmt.Errorf("unknown tree shape %v", proof.Shape)
+	}
+	h, err := lookupHasher(proof.Algorithm, proof.Mode)
+	if err != nil {
+		return err
+	}
+	if proof.OldSize <= 0 || proof.OldSize > proof.NewSize {
+		return fmt.Errorf("invalid tree sizes %d and %d", proof.OldSize, proof.NewSize)
+	}
+
+	v := consistencyVerifier{hasher: h, shape: proof.Shape, oldSize: proof.OldSize, oldRoot: oldRoot.Hash, hashes: proof.Hashes}
+	oldHash, newHash, err := v.verify(proof.OldSize, proof.NewSize, height(proof.NewSize), true)
+	if err != nil {
+		return err
+	}
+	if len(v.hashes) != 0 {
+		return fmt.Errorf("proof has %d unused hashes", len(v.hashes))
+	}
+
+	// The old root was rebuilt at the height of the new tree.
+	expected := oldRoot.Hash
+	for l := height(proof.OldSize); l < height(proof.NewSize); l++ {
+		expected = h.unpaired(expected, proof.Shape)
+	}
+	if !bytes.Equal(oldHash, expected) {
+		return errors.New("computed old root does not match")
+	}
+	if !bytes.Equal(newHash, newRoot.Hash) {
+		return errors.New("computed new root does not match")
+	}
+	return nil
+}
+
+// consistencyVerifier mirrors MerkleTree.subproof, consuming proof hashes in
+// the order they were produced.
+type consistencyVerifier struct {
+	hasher  hasher
+	shape   Shape
+	oldSize int
+	oldRoot []byte
+	hashes  [][]byte
+}
+
+func (v *consistencyVerifier) next() ([]byte, error) {
+	if len(v.hashes) == 0 {
+		return nil, errors.New("proof is too short")
+	}
+	hash := v.hashes[0]
+	v.hashes = v.hashes[1:]
+	return hash, nil
+}
+
+// verify returns the hashes of the nodes over the first m and over all n
+// leaves at height h.
+func (v *consistencyVerifier) verify(m, n, h int, complete bool) ([]byte, []byte, error) {
+	if m == n {
+		if complete {
+			hash := v.oldRoot
+			for l := height(v.oldSize); l < h; l++ {
+				hash = v.hasher.unpaired(hash, v.shape)
+			}
+			return hash, hash, nil
+		}
+		hash, err := v.next()
+		return hash, hash, err
+	}
+
+	k := 1 << (h - 1)
+	if n <= k {
+		oldHash, newHash, err := v.verify(m, n, h-1, complete)
+		if err != nil {
+			return nil, nil, err
+		}
+		return v.hasher.unpaired(oldHash, v.shape), v.hasher.unpaired(newHash, v.shape), nil
+	}
+	if m <= k {
+		oldHash, newHash, err := v.verify(m, k, h-1, complete)
+		if err != nil {
+			return nil, nil, err
+		}
+		right, err := v.next()
+		if err != nil {
+			return nil, nil, err
+		}
+		return v.hasher.unpaired(oldHash, v.shape), v.hasher.children(newHash, right), nil
+	}
+	oldHash, newHash, err := v.verify(m-k, n-k, h-1, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	left, err := v.next()
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.hasher.children(left, oldHash), v.hasher.children(left, newHash), nil
+}
@@ -0,0 +1,99 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRootAtMatchesBatchBuild(t *testing.T) {
+	var leaves [][]byte
+	for i := 0; i < 20; i++ {
+		leaves = append(leaves, []byte{byte(i)})
+	}
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddLeaves(leaves)
+
+	for size := 1; size <= len(leaves); size++ {
+		batch := NewMerkleTree(WithHashMode(HashModeRFC6962))
+		batch.AddLeaves(leaves[:size])
+		root, err := mt.RootAt(size)
+		if err != nil {
+			t.Fatalf("Failed to compute root at %d: %v", size, err)
+		}
+		if !bytes.Equal(root.Hash, batch.Root.Hash) {
+			t.Errorf("Root at size %d differs from batch root", size)
+		}
+	}
+	if _, err := mt.RootAt(21); err == nil {
+		t.Error("Root beyond the tree size should fail")
+	}
+}
+
+func TestConsistencyProof(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	for i := 0; i < 20; i++ {
+		mt.AddFile([]byte{byte(i)})
+	}
+
+	for newSize := 1; newSize <= 20; newSize++ {
+		newRoot, _ := mt.RootAt(newSize)
+		for oldSize := 1; oldSize <= newSize; oldSize++ {
+			oldRoot, _ := mt.RootAt(oldSize)
+			proof, err := mt.ConsistencyProof(oldSize, newSize)
+			if err != nil {
+				t.Fatalf("Failed to generate proof: %v", err)
+			}
+			if err := VerifyConsistency(proof, oldRoot, newRoot); err != nil {
+				t.Errorf("Proof from %d to %d did not verify: %v", oldSize, newSize, err)
+			}
+
+			if oldSize < newSize {
+				other, _ := mt.RootAt(oldSize + 1)
+				if err := VerifyConsistency(proof, other, newRoot); err == nil {
+					t.Errorf("Proof from %d to %d should not verify with another old root", oldSize, newSize)
+				}
+			}
+			for i := range proof.Hashes {
+				tampered := *proof
+				tampered.Hashes = append([][]byte(nil), proof.Hashes...)
+				tampered.Hashes[i] = oldRoot.Hash
+				if bytes.Equal(proof.Hashes[i], oldRoot.Hash) {
+					continue
+				}
+				if err := VerifyConsistency(&tampered, oldRoot, newRoot); err == nil {
+					t.Errorf("Tampered proof from %d to %d should not verify", oldSize, newSize)
+				}
+			}
+		}
+	}
+}
+
+func TestConsistencyProofDetectsRewrite(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	for i := 0; i < 7; i++ {
+		mt.AddFile([]byte{byte(i)})
+	}
+	oldRoot, _ := mt.RootDigest()
+
+	mt.UpdateLeaf(2, []byte("rewritten"))
+	mt.AddFile([]byte{7})
+	newRoot, _ := mt.RootDigest()
+
+	proof, err := mt.ConsistencyProof(7, 8)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if err := VerifyConsistency(proof, oldRoot, newRoot); err == nil {
+		t.Error("Proof should not verify after history was rewritten")
+	}
+}
+
+func TestConsistencyProofInvalidSizes(t *testing.T) {
+	mt := NewMerkleTree()
+	mt.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+	for _, sizes := range [][2]int{{0, 2}, {3, 2}, {1, 4}} {
+		if _, err := mt.ConsistencyProof(sizes[0], sizes[1]); err == nil {
+			t.Errorf("Proof from %d to %d should fail", sizes[0], sizes[1])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Subtree names the perfect subtree of 2^Height leaves that starts at leaf
+// Index<<Height. Its hash is the same in every shape.
+type Subtree struct {
+	Height int
+	Index  int
+}
+
+// LeafRange is the half-open range of leaf indices [Start, End).
+type LeafRange struct {
+	Start int
+	End   int
+}
+
+// SubtreeHashes returns the hash of each subtree, all of whose leaves must be
+// in the tree.
+func (mt *MerkleTree) SubtreeHashes(subtrees []Subtree) ([][]byte, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	hashes := make([][]byte, len(subtrees))
+	for i, st := range subtrees {
+		if st.Height < 0 || st.Index < 0 || st.Height >= len(mt.levels) || st.Index >= len(mt.Leaves)>>st.Height {
+			return nil, fmt.Errorf("subtree %d at height %d is not in the tree", st.Index, st.Height)
+		}
+		hashes[i] = mt.levels[st.Height][st.Index].Hash
+	}
+	return hashes, nil
+}
+
+// Diff returns, in ascending order, the ranges of leaf indices at which a and
+// b differ. Leaves past the end of the smaller tree always differ. The trees
+// are walked top-down and only subtrees whose hashes differ are opened, so d
+// differing leaves cost O(d log n) comparisons. Both trees must use the same
+// algorithm and mode; their shapes may differ.
+//
+// The trees are read level by level rather than at a single version, so
+// changes made during the walk may or may not be reported.
+func Diff(a, b *MerkleTree) ([]LeafRange, error) {
+	if a.Algorithm() != b.Algorithm() || a.Mode() != b.Mode() {
+		return nil, errors.New("trees use different algorithms or modes")
+	}
+	return DiffRemote(a, b.Size(), b.SubtreeHashes)
+}
+
+// DiffRemote is Diff against a tree that is only reachable through remote,
+// which must return the hashes of the given subtrees of a tree of remoteSize
+// leaves, as SubtreeHashes does. remote is called at most once per level with
+// every subtree of that level that needs comparing, so a peer can be diffed in
+// O(log n) round trips.
+func DiffRemote(local *MerkleTree, remoteSize int, remote func([]Subtree) ([][]byte, error)) ([]LeafRange, error) {
+	localSize := local.Size()
+	minSize, maxSize := localSize, remoteSize
+	if minSize > maxSize {
+		minSize, maxSize = maxSize, minSize
+	}
+	if maxSize == 0 {
+		return nil, nil
+	}
+
+	var ranges []LeafRange
+	frontier := []Subtree{{Height: height(maxSize), Index: 0}}
+	for len(frontier) > 0 {
+		var compare, next []Subtree
+		for _, st := range frontier {
+			start, end := st.Index<<st.Height, (st.Index+1)<<st.Height
+			switch {
+			case start >= maxSize:
+			case start >= minSize:
+				if end > maxSize {
+					end = maxSize
+				}
+				ranges = append(ranges, LeafRange{Start: start, End: end})
+			case end <= minSize:
+				compare = append(compare, st)
+			default:
+				// The subtree is only partly in the smaller tree, so
+				// its hashes cannot be compared; open it.
+				next = append(next, halves(st)...)
+			}
+		}
+
+		if len(compare) > 0 {
+			localHashes, err := local.SubtreeHashes(compare)
+			if err != nil {
+				return nil, err
+			}
+			remoteHashes, err := remote(compare)
+			if err != nil {
+				return nil, err
+			}
+			if len(remoteHashes) != len(compare) {
+				return nil, fmt.Errorf("got %d subtree hashes, expected %d", len(remoteHashes), len(compare))
+			}
+			for i, st := range compare {
+				switch {
+				case bytes.Equal(localHashes[i], remoteHashes[i]):
+				case st.Height == 0:
+					ranges = append(ranges, LeafRange{Start: st.Index, End: st.Index + 1})
+				default:
+					next = append(next, halves(st)...)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return mergeRanges(ranges), nil
+}
+
+// halves returns the two halves of a subtree.
+func halves(st Subtree) []Subtree {
+	return []Subtree{
+		{Height: st.Height - 1, Index: 2 * st.Index},
+		{Height: st.Height - 1, Index: 2*st.Index + 1},
+	}
+}
+
+// mergeRanges sorts disjoint ranges and joins the adjacent ones.
+func mergeRanges(ranges []LeafRange) []LeafRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	var merged []LeafRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && merged[n-1].End == r.Start {
+			merged[n-1].End = r.End
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,121 @@
+package merkle
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// naiveDiff compares every leaf of a and b.
+func naiveDiff(a, b [][]byte) []LeafRange {
+	var ranges []LeafRange
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i < len(a) && i < len(b) && string(a[i]) == string(b[i]) {
+			continue
+		}
+		ranges = append(ranges, LeafRange{Start: i, End: i + 1})
+	}
+	return mergeRanges(ranges)
+}
+
+func TestDiff(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 200; iter++ {
+		var leavesA, leavesB [][]byte
+		for i, n := 0, rng.Intn(70); i < n; i++ {
+			leavesA = append(leavesA, []byte(fmt.Sprint(i)))
+		}
+		leavesB = append(leavesB, leavesA...)
+		if cut := rng.Intn(len(leavesB) + 1); rng.Intn(2) == 0 {
+			leavesB = leavesB[:cut]
+		}
+		for i, n := 0, rng.Intn(5); i < n; i++ {
+			leavesB = append(leavesB, []byte(fmt.Sprint("extra", i)))
+		}
+		for i, n := 0, rng.Intn(4); i < n && len(leavesB) > 0; i++ {
+			leavesB[rng.Intn(len(leavesB))] = []byte(fmt.Sprint("changed", i))
+		}
+
+		a := NewMerkleTree()
+		a.AddLeaves(leavesA)
+		b := NewMerkleTree(WithShape(ShapeDuplicate))
+		for _, leaf := range leavesB {
+			b.AddFile(leaf)
+		}
+
+		want := naiveDiff(leavesA, leavesB)
+		got, err := Diff(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("sizes %d and %d: got %v, want %v", len(leavesA), len(leavesB), got, want)
+		}
+		got, err = Diff(b, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("sizes %d and %d, swapped: got %v, want %v", len(leavesA), len(leavesB), got, want)
+		}
+	}
+}
+
+func TestDiffRemoteOpensOnlyDifferingSubtrees(t *testing.T) {
+	const size = 1 << 12
+	a, b := NewMerkleTree(), NewMerkleTree()
+	for i := 0; i < size; i++ {
+		a.AddFile([]byte(fmt.Sprint(i)))
+		b.AddFile([]byte(fmt.Sprint(i)))
+	}
+	b.UpdateLeaf(100, []byte("changed"))
+	b.RemoveLeaf(3000)
+
+	calls, compared := 0, 0
+	got, err := DiffRemote(a, b.Size(), func(subtrees []Subtree) ([][]byte, error) {
+		calls++
+		compared += len(subtrees)
+		return b.SubtreeHashes(subtrees)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LeafRange{{Start: 100, End: 101}, {Start: 3000, End: 3001}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if calls > height(size)+1 || compared > 2*2*(height(size)+1) {
+		t.Errorf("compared %d subtrees in %d calls", compared, calls)
+	}
+
+	if _, err := Diff(a, NewMerkleTree(WithHashMode(HashModeRFC6962))); err == nil {
+		t.Error("diffed trees with different modes")
+	}
+	if _, err := a.SubtreeHashes([]Subtree{{Height: 13, Index: 0}}); err == nil {
+		t.Error("hashed a subtree past the end of the tree")
+	}
+}
+
+func TestSubtreeHashesRejectsOutOfRange(t *testing.T) {
+	mt := NewMerkleTree()
+	for i := 0; i < 16; i++ {
+		mt.AddFile([]byte(fmt.Sprint(i)))
+	}
+	for _, st := range []Subtree{
+		{Height: 3, Index: 1 << 61},
+		{Height: 0, Index: math.MaxInt},
+		{Height: 1, Index: math.MaxInt >> 1},
+		{Height: 2, Index: 4},
+		{Height: math.MaxInt, Index: 0},
+		{Height: 0, Index: -1},
+	} {
+		if _, err := mt.SubtreeHashes([]Subtree{st}); err == nil {
+			t.Errorf("hashed subtree %+v of a 16-leaf tree", st)
+		}
+	}
+	if _, err := mt.SubtreeHashes([]Subtree{{Height: 2, Index: 3}, {Height: 4, Index: 0}}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,211 @@
+package merkle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Dump describes every node of a version of a tree, for debugging. It can be
+// written as Graphviz DOT or JSON. Dumps hold the whole tree and are meant for
+// trees small enough to look at.
+type Dump struct {
+	Algorithm Algorithm `json:"algorithm"`
+	Mode      string    `json:"mode"`
+	Shape     string    `json:"shape"`
+	Size      int       `json:"size"`
+	Root      string    `json:"root"`
+	// Levels holds the nodes level by level, from the leaves up to the
+	// root.
+	Levels [][]DumpNode `json:"levels"`
+	// LeafIndex is the leaf whose proof is highlighted, or -1.
+	LeafIndex int `json:"leaf_index"`
+}
+
+// DumpNode is one node of a Dump. Hashes are hex encoded.
+type DumpNode struct {
+	Hash string `json:"hash"`
+	// Start and End are the range of leaf indices [Start, End) under the
+	// node.
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Removed marks a tombstoned leaf.
+	Removed bool `json:"removed,omitempty"`
+	// Promoted marks a node moved up unchanged from the level below.
+	Promoted bool `json:"promoted,omitempty"`
+	// Path marks the highlighted leaf and its ancestors, and Sibling the
+	// nodes whose hashes its proof supplies. ProofHash is the hash the
+	// proof supplied instead, if it differs.
+	Path      bool   `json:"path,omitempty"`
+	Sibling   bool   `json:"sibling,omitempty"`
+	ProofHash string `json:"proof_hash,omitempty"`
+}
+
+// Dump describes the snapshot. If proof is not nil, the path of its leaf is
+// highlighted and its sibling hashes are compared with the snapshot's, so a
+// proof that fails to verify shows where it goes wrong.
+func (s *Snapshot) Dump(proof *Proof) (*Dump, error) {
+	levels := s.getLevels()
+	d := &Dump{
+		Algorithm: s.hasher.alg,
+		Mode:      s.hasher.mode.String(),
+		Shape:     s.shape.String(),
+		Size:      s.leaves.n,
+		Root:      hex.EncodeToString(s.root),
+		Levels:    make([][]DumpNode, len(levels)),
+		LeafIndex: -1,
+	}
+	tombstone := s.hasher.tombstone()
+	for l, hashes := range levels {
+		d.Levels[l] = make([]DumpNode, len(hashes))
+		for i, hash := range hashes {
+			end := (i + 1) << l
+			if end > d.Size {
+				end = d.Size
+			}
+			d.Levels[l][i] = DumpNode{
+				Hash:     hex.EncodeToString(hash),
+				Start:    i << l,
+				End:      end,
+				Removed:  l == 0 && bytes.Equal(hash, tombstone),
+				Promoted: l > 0 && s.shape == ShapePromote && 2*i+1 == len(levels[l-1]),
+			}
+		}
+	}
+	if proof == nil {
+		return d, nil
+	}
+
+	switch {
+	case proof.Algorithm != s.hasher.alg || proof.Mode != s.hasher.mode || proof.Shape != s.shape:
+		return nil, errors.New("proof is for a tree with another algorithm, mode or shape")
+	case proof.TreeSize != d.Size:
+		return nil, fmt.Errorf("proof is for tree size %d, snapshot has %d leaves", proof.TreeSize, d.Size)
+	case proof.LeafIndex < 0 || proof.LeafIndex >= d.Size:
+		return nil, errors.New("invalid leaf index")
+	}
+	d.LeafIndex = proof.LeafIndex
+	i, k := proof.LeafIndex, 0
+	for l, nodes := range d.Levels {
+		nodes[i].Path = true
+		if l == len(d.Levels)-1 {
+			break
+		}
+		// Find the sibling the way Snapshot.GenerateProof does.
+		sibling := i + 1
+		if i%2 == 1 {
+			sibling = i - 1
+		} else if sibling == len(nodes) {
+			sibling = i
+		}
+		if sibling != i || s.shape == ShapeDuplicate {
+			nodes[sibling].Sibling = true
+			if k >= len(proof.Siblings) {
+				return nil, fmt.Errorf("proof has %d siblings, the path needs more", len(proof.Siblings))
+			}
+			if hash := hex.EncodeToString(proof.Siblings[k]); hash != nodes[sibling].Hash {
+				nodes[sibling].ProofHash = hash
+			}
+			k++
+		}
+		i /= 2
+	}
+	if k != len(proof.Siblings) {
+		return nil, fmt.Errorf("proof has %d siblings, the path needs %d", len(proof.Siblings), k)
+	}
+	return d, nil
+}
+
+// Dump describes the current version of the tree, or the version proof was
+// generated against if proof is not nil. See Snapshot.Dump.
+func (mt *MerkleTree) Dump(proof *Proof) (*Dump, error) {
+	if proof == nil {
+		s, err := mt.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		return s.Dump(nil)
+	}
+	s, err := mt.SnapshotAt(proof.TreeSize)
+	if err != nil {
+		return nil, err
+	}
+	return s.Dump(proof)
+}
+
+// WriteJSON writes the dump as indented JSON.
+func (d *Dump) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteDOT writes the dump as a Graphviz digraph, root at the top. Labels
+// show the first 16 hex digits of each hash; tooltips show all of them. The
+// highlighted path is blue, the proof's siblings yellow, and siblings whose
+// hash the proof gets wrong red.
+func (d *Dump) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph merkle {\n")
+	fmt.Fprintf(bw, "\tlabel=%q;\n", fmt.Sprintf("%s %s %s, %d leaves, root %s", d.Algorithm, d.Mode, d.Shape, d.Size, d.Root))
+	fmt.Fprintf(bw, "\tnode [shape=box, fontname=monospace];\n")
+	for l := len(d.Levels) - 1; l >= 0; l-- {
+		fmt.Fprintf(bw, "\t{ rank=same;")
+		for i := range d.Levels[l] {
+			fmt.Fprintf(bw, " n%d_%d;", l, i)
+		}
+		fmt.Fprintf(bw, " }\n")
+	}
+
+	for l, nodes := range d.Levels {
+		for i, n := range nodes {
+			label := fmt.Sprintf("[%d, %d)", n.Start, n.End)
+			if l == 0 {
+				label = fmt.Sprintf("leaf %d", i)
+			}
+			label += "\n" + shortHash(n.Hash)
+			attrs := ""
+			switch {
+			case n.ProofHash != "":
+				label += "\nproof: " + shortHash(n.ProofHash)
+				attrs = ", style=filled, fillcolor=salmon"
+			case n.Path:
+				attrs = ", style=filled, fillcolor=lightblue"
+			case n.Sibling:
+				attrs = ", style=filled, fillcolor=lightyellow"
+			case n.Removed:
+				attrs = ", style=dashed"
+			}
+			fmt.Fprintf(bw, "\tn%d_%d [label=%q, tooltip=%q%s];\n", l, i, label, n.Hash, attrs)
+
+			if l == 0 {
+				continue
+			}
+			left, right := 2*i, 2*i+1
+			switch {
+			case n.Promoted:
+				fmt.Fprintf(bw, "\tn%d_%d -> n%d_%d [style=dotted];\n", l, i, l-1, left)
+			case right == len(d.Levels[l-1]):
+				// The last node of an odd level is duplicated.
+				fmt.Fprintf(bw, "\tn%d_%d -> n%d_%d [label=\"x2\"];\n", l, i, l-1, left)
+			default:
+				fmt.Fprintf(bw, "\tn%d_%d -> n%d_%d;\n", l, i, l-1, left)
+				fmt.Fprintf(bw, "\tn%d_%d -> n%d_%d;\n", l, i, l-1, right)
+			}
+		}
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
+
+// shortHash abbreviates a hex hash for a label.
+func shortHash(hash string) string {
+	if len(hash) <= 16 {
+		return hash
+	}
+	return hash[:16] + "..."
+}
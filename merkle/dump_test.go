@@ -0,0 +1,73 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	for i := 0; i < 5; i++ {
+		mt.AddFile([]byte(fmt.Sprint(i)))
+	}
+	mt.RemoveLeaf(1)
+	proof, err := mt.GenerateProof(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := mt.Dump(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := mt.RootDigest()
+	if d.Root != hex.EncodeToString(root.Hash) || d.Size != 5 || len(d.Levels) != 4 || d.LeafIndex != 4 {
+		t.Fatalf("unexpected dump header: %+v", d)
+	}
+	if !d.Levels[0][1].Removed || !d.Levels[1][2].Promoted || !d.Levels[2][1].Promoted {
+		t.Error("removed or promoted nodes are not marked")
+	}
+	for l, i := range []int{4, 2, 1, 0} {
+		if !d.Levels[l][i].Path {
+			t.Errorf("node %d at level %d is not on the path", i, l)
+		}
+	}
+	if !d.Levels[2][0].Sibling || d.Levels[2][0].ProofHash != "" {
+		t.Error("the proof's only sibling is not marked")
+	}
+
+	var decoded Dump
+	var buf bytes.Buffer
+	if err := d.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Levels[3][0].Hash != d.Root {
+		t.Errorf("JSON dump does not round trip: %v", err)
+	}
+
+	proof.Siblings[0] = bytes.Repeat([]byte{1}, len(proof.Siblings[0]))
+	d, err = mt.Dump(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Levels[2][0].ProofHash != hex.EncodeToString(proof.Siblings[0]) {
+		t.Error("the tampered sibling is not marked")
+	}
+	buf.Reset()
+	if err := d.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	if !strings.HasPrefix(dot, "digraph merkle {") || !strings.Contains(dot, "n2_0 [label=\"[0, 4)\\n") || !strings.Contains(dot, "salmon") {
+		t.Errorf("unexpected DOT output:\n%s", dot)
+	}
+
+	proof.Siblings = append(proof.Siblings, proof.Siblings[0])
+	if _, err := mt.Dump(proof); err == nil {
+		t.Error("dumped a proof with too many siblings")
+	}
+}
@@ -0,0 +1,224 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// TreeStateVersion is the version of the TreeState layout and of the
+// MarshalBinary format written by this package. Version 2 added the tree
+// shape; version 1 states, which predate it, are still read as
+// ShapeDuplicate trees.
+const TreeStateVersion = 2
+
+// TreeState is everything needed to rebuild a MerkleTree.
+type TreeState struct {
+	Version   int
+	Algorithm Algorithm
+	Mode      HashMode
+	Shape     Shape
+	// Leaves holds the leaf hashes in order, tombstones included.
+	Leaves [][]byte
+	// RootHash is the root the rebuilt tree must have, or nil for an empty
+	// tree.
+	RootHash []byte
+}
+
+// State returns the state of the tree.
+func (mt *MerkleTree) State() TreeState {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	state := TreeState{
+		Version:   TreeStateVersion,
+		Algorithm: mt.hasher.alg,
+		Mode:      mt.hasher.mode,
+		Shape:     mt.shape,
+		Leaves:    make([][]byte, len(mt.Leaves)),
+	}
+	for i, leaf := range mt.Leaves {
+		state.Leaves[i] = bytes.Clone(leaf.Hash)
+	}
+	if mt.Root != nil {
+		state.RootHash = bytes.Clone(mt.Root.Hash)
+	}
+	return state
+}
+
+// NewMerkleTreeFromState rebuilds a tree from its state. The hash algorithm
+// must be registered, and the rebuilt root must match state.RootHash. Of
+// opts, only WithSnapshots and WithWorkers apply: the state fixes the rest.
+func NewMerkleTreeFromState(state TreeState, opts ...Option) (*MerkleTree, error) {
+	mt, err := buildFromState(state, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if mt.Root == nil && state.RootHash != nil {
+		return nil, errors.New("empty tree cannot have a root")
+	}
+	if mt.Root != nil && !bytes.Equal(mt.Root.Hash, state.RootHash) {
+		return nil, errors.New("rebuilt root does not match the saved root")
+	}
+	return mt, nil
+}
+
+// buildFromState rebuilds a tree from the leaves of state, ignoring its root.
+func buildFromState(state TreeState, opts ...Option) (*MerkleTree, error) {
+	switch {
+	case state.Version == 1 && state.Shape != ShapeDuplicate:
+		return nil, fmt.Errorf("tree state version 1 cannot have shape %v", state.Shape)
+	case state.Version != 1 && state.Version != TreeStateVersion:
+		return nil, fmt.Errorf("unsupported tree state version %d", state.Version)
+	case !state.Shape.valid():
+		return nil, fmt.Errorf("unknown tree shape %v", state.Shape)
+	}
+	h, err := lookupHasher(state.Algorithm, state.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newConfig(opts)
+	mt := &MerkleTree{
+		Leaves:  make([]*Node, len(state.Leaves)),
+		hasher:  h,
+		shape:   state.Shape,
+		index:   make(map[string][]int),
+		retain:  c.snapshots,
+		workers: c.workers,
+	}
+	size := h.newHash().Size()
+	for i, hash := range state.Leaves {
+		if len(hash) != size {
+			return nil, fmt.Errorf("leaf %d has %d bytes, expected %d", i, len(hash), size)
+		}
+		mt.Leaves[i] = &Node{Hash: bytes.Clone(hash)}
+	}
+	if len(mt.Leaves) == 0 {
+		return mt, nil
+	}
+
+	// Rebuilding also re-links every parent and rebuilds the leaf index.
+	if err := mt.recalculateTree(); err != nil {
+		return nil, err
+	}
+	mt.recordSnapshot()
+	return mt, nil
+}
+
+// MarshalBinary encodes the state of the tree as a version byte, the hash
+// mode and shape bytes, the length-prefixed algorithm name, the number of
+// leaves and the hash size as uvarints, then the leaf hashes and the root
+// hash. Version 1 had no shape byte.
+func (mt *MerkleTree) MarshalBinary() ([]byte, error) {
+	state := mt.State()
+	if len(state.Algorithm) > 255 {
+		return nil, fmt.Errorf("algorithm name too long: %q", state.Algorithm)
+	}
+	size := mt.hasher.newHash().Size()
+
+	buf := make([]byte, 0, 4+len(state.Algorithm)+2*binary.MaxVarintLen64+(len(state.Leaves)+1)*size)
+	buf = append(buf, TreeStateVersion, byte(state.Mode), byte(state.Shape), byte(len(state.Algorithm)))
+	buf = append(buf, state.Algorithm...)
+	buf = binary.AppendUvarint(buf, uint64(len(state.Leaves)))
+	buf = binary.AppendUvarint(buf, uint64(size))
+	for _, leaf := range state.Leaves {
+		buf = append(buf, leaf...)
+	}
+	buf = append(buf, state.RootHash...)
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the tree with the one encoded in data by
+// MarshalBinary, rebuilding every node. A tree made by NewMerkleTree keeps its
+// WithSnapshots and WithWorkers settings; a zero MerkleTree gets the defaults.
+func (mt *MerkleTree) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 {
+		return errors.New("tree state too short")
+	}
+	state := TreeState{Version: int(data[0]), Mode: HashMode(data[1])}
+	switch state.Version {
+	case 1:
+		data = data[2:]
+	case TreeStateVersion:
+		state.Shape = Shape(data[2])
+		data = data[3:]
+	default:
+		return fmt.Errorf("unsupported tree state version %d", state.Version)
+	}
+	if len(data) < 1 {
+		return errors.New("tree state too short")
+	}
+	algLen := int(data[0])
+	data = data[1:]
+	if len(data) < algLen {
+		return errors.New("tree state too short")
+	}
+	state.Algorithm = Algorithm(data[:algLen])
+	data = data[algLen:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("invalid leaf count")
+	}
+	data = data[n:]
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size == 0 {
+		return errors.New("invalid hash size")
+	}
+	data = data[n:]
+
+	// Bound count by the data before multiplying, so that neither count*size
+	// nor the root added to it can overflow.
+	if count > uint64(len(data))/size {
+		return fmt.Errorf("tree state has %d bytes of hashes, too few for %d of %d bytes", len(data), count, size)
+	}
+	expected := count * size
+	if count > 0 {
+		expected += size
+	}
+	if uint64(len(data)) != expected {
+		return fmt.Errorf("tree state has %d bytes of hashes, expected %d", len(data), expected)
+	}
+	for i := uint64(0); i < count; i++ {
+		state.Leaves = append(state.Leaves, data[:size])
+		data = data[size:]
+	}
+	if count > 0 {
+		state.RootHash = data
+	}
+
+	loaded, err := NewMerkleTreeFromState(state, mt.options()...)
+	if err != nil {
+		return err
+	}
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	mt.Root, mt.Leaves, mt.hasher, mt.shape = loaded.Root, loaded.Leaves, loaded.hasher, loaded.shape
+	mt.levels, mt.index, mt.levelCache = loaded.levels, loaded.index, loaded.levelCache
+	mt.hashes, mt.current, mt.history = loaded.hashes, loaded.current, loaded.history
+	mt.retain, mt.workers = loaded.retain, loaded.workers
+	return nil
+}
+
+// Migrate returns a copy of the tree rebuilt in another shape, such as a
+// format 1 tree moved to format 2. The leaves and their indices carry over,
+// as do the WithSnapshots and WithWorkers settings; the roots, proofs and
+// snapshots of the old tree do not.
+func (mt *MerkleTree) Migrate(shape Shape) (*MerkleTree, error) {
+	state := mt.State()
+	state.Version = TreeStateVersion
+	state.Shape = shape
+	return buildFromState(state, mt.options()...)
+}
+
+// options returns the settings of the tree that its state does not hold, or
+// none for a zero MerkleTree.
+func (mt *MerkleTree) options() []Option {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	if mt.index == nil {
+		return nil
+	}
+	return []Option{WithSnapshots(mt.retain), WithWorkers(mt.workers)}
+}
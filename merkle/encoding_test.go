@@ -0,0 +1,147 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	newHash, _ := LookupAlgorithm(SHA512_256)
+	for size := 0; size <= 9; size++ {
+		mt := NewMerkleTree(WithHash(SHA512_256, newHash), WithHashMode(HashModeRFC6962))
+		for i := 0; i < size; i++ {
+			mt.AddEntry(string(rune('a'+i)), []byte{byte(i)})
+		}
+		if size > 2 {
+			mt.RemoveLeaf(1)
+		}
+
+		data, err := mt.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal tree: %v", err)
+		}
+		var loaded MerkleTree
+		if err := loaded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Failed to unmarshal tree of %d leaves: %v", size, err)
+		}
+
+		if loaded.Algorithm() != SHA512_256 || loaded.Mode() != HashModeRFC6962 || len(loaded.Leaves) != size {
+			t.Errorf("Loaded tree has algorithm %q, mode %v and %d leaves", loaded.Algorithm(), loaded.Mode(), len(loaded.Leaves))
+		}
+		if size == 0 {
+			continue
+		}
+		if !bytes.Equal(loaded.Root.Hash, mt.Root.Hash) {
+			t.Errorf("Loaded root differs for %d leaves", size)
+		}
+		if size > 2 && !loaded.IsRemoved(1) {
+			t.Errorf("Tombstone should survive a round trip")
+		}
+
+		// Parents are re-linked, so proofs and further appends work.
+		root, _ := loaded.RootDigest()
+		proof, err := loaded.GenerateProof(size - 1)
+		if err != nil {
+			t.Fatalf("Failed to generate proof: %v", err)
+		}
+		if err := VerifyEntryProof(string(rune('a'+size-1)), []byte{byte(size - 1)}, proof, root); err != nil {
+			t.Errorf("Proof from loaded tree did not verify: %v", err)
+		}
+		if indices := loaded.GetIndicesFromEntry("a", []byte{0}); len(indices) != 1 {
+			t.Errorf("Loaded tree should index its leaves, got %v", indices)
+		}
+		loaded.AddEntry("z", []byte("z"))
+		mt.AddEntry("z", []byte("z"))
+		if !bytes.Equal(loaded.Root.Hash, mt.Root.Hash) {
+			t.Errorf("Loaded tree diverged after an append")
+		}
+	}
+}
+
+func TestUnmarshalBinaryRejectsInvalidData(t *testing.T) {
+	mt := NewMerkleTree()
+	mt.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+	data, _ := mt.MarshalBinary()
+
+	newVersion := bytes.Clone(data)
+	newVersion[0] = TreeStateVersion + 1
+	corruptRoot := bytes.Clone(data)
+	corruptRoot[len(corruptRoot)-1] ^= 0xff
+	unknownAlg := append([]byte{TreeStateVersion, 0, 3}, "md5"...)
+	unknownAlg = append(unknownAlg, data[3+len(SHA256):]...)
+	header := data[:4+len(SHA256)]
+	hugeHash := binary.AppendUvarint(append([]byte(nil), header...), 1)
+	hugeHash = binary.AppendUvarint(hugeHash, 1<<63+2)
+	hugeHash = append(hugeHash, make([]byte, 64)...)
+	hugeCount := binary.AppendUvarint(append([]byte(nil), header...), 1<<62)
+	hugeCount = binary.AppendUvarint(hugeCount, 4)
+	hugeCount = append(hugeCount, make([]byte, 64)...)
+
+	for name, invalid := range map[string][]byte{
+		"empty":             nil,
+		"truncated":         data[:len(data)-1],
+		"newer version":     newVersion,
+		"corrupt root":      corruptRoot,
+		"unknown algorithm": unknownAlg,
+		"huge hash size":    hugeHash,
+		"huge leaf count":   hugeCount,
+	} {
+		var loaded MerkleTree
+		if err := loaded.UnmarshalBinary(invalid); err == nil {
+			t.Errorf("Loading %s data should fail", name)
+		}
+	}
+}
+
+func TestNewMerkleTreeFromState(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+
+	loaded, err := NewMerkleTreeFromState(mt.State())
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if !bytes.Equal(loaded.Root.Hash, mt.Root.Hash) {
+		t.Errorf("Loaded root differs")
+	}
+
+	state := mt.State()
+	state.Leaves = state.Leaves[:2]
+	if _, err := NewMerkleTreeFromState(state); err == nil {
+		t.Error("State with missing leaves should not load")
+	}
+}
+
+func TestRebuiltTreesKeepOptions(t *testing.T) {
+	source := NewMerkleTree()
+	source.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+	data, _ := source.MarshalBinary()
+
+	loaded := NewMerkleTree(WithSnapshots(1), WithWorkers(1))
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	migrated, err := loaded.Migrate(ShapeDuplicate)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	for name, mt := range map[string]*MerkleTree{"loaded": loaded, "migrated": migrated} {
+		if mt.retain != 1 || mt.workers != 1 {
+			t.Errorf("%s tree keeps %d snapshots with %d workers, expected 1 and 1", name, mt.retain, mt.workers)
+		}
+		mt.AddFile([]byte{'d'})
+		mt.AddFile([]byte{'e'})
+		if _, err := mt.SnapshotAt(3); err == nil {
+			t.Errorf("%s tree kept more than one past snapshot", name)
+		}
+	}
+
+	var zero MerkleTree
+	if err := zero.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if zero.retain != DefaultSnapshots {
+		t.Errorf("Zero tree keeps %d snapshots, expected %d", zero.retain, DefaultSnapshots)
+	}
+}
@@ -0,0 +1,167 @@
+package merkle
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Entry is the leaf data of a named file. Committing to the name and size as
+// well as the content means a proof shows that a given file has given bytes,
+// and two files with the same content get different leaves.
+type Entry struct {
+	Name        string
+	Size        uint64
+	ContentHash []byte
+}
+
+// NewEntry returns the entry for a named file, hashing its chunks with alg
+// and mode.
+func NewEntry(alg Algorithm, mode HashMode, name string, content []byte) (Entry, error) {
+	h, err := lookupHasher(alg, mode)
+	if err != nil {
+		return Entry{}, err
+	}
+	return h.entry(name, content), nil
+}
+
+// Encode returns the leaf data for the entry: the name length as a 4-byte
+// big-endian integer, the name, the size as an 8-byte big-endian integer and
+// the content hash.
+func (e Entry) Encode() []byte {
+	buf := make([]byte, 0, 4+len(e.Name)+8+len(e.ContentHash))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(e.Name)))
+	buf = append(buf, e.Name...)
+	buf = binary.BigEndian.AppendUint64(buf, e.Size)
+	buf = append(buf, e.ContentHash...)
+	return buf
+}
+
+// DecodeEntry parses leaf data produced by Entry.Encode.
+func DecodeEntry(data []byte) (Entry, error) {
+	if len(data) < 4 {
+		return Entry{}, errors.New("entry too short")
+	}
+	nameLen := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(len(data)) < uint64(nameLen)+8 {
+		return Entry{}, errors.New("entry too short")
+	}
+	return Entry{
+		Name:        string(data[:nameLen]),
+		Size:        binary.BigEndian.Uint64(data[nameLen:]),
+		ContentHash: append([]byte(nil), data[nameLen+8:]...),
+	}, nil
+}
+
+// entry returns the entry for a named file. The content hash is the root of
+// the file's chunk tree.
+func (h hasher) entry(name string, content []byte) Entry {
+	return Entry{
+		Name:        name,
+		Size:        uint64(len(content)),
+		ContentHash: h.chunkRoot(content),
+	}
+}
+
+// EntryWriter computes the entry of a file written to it in pieces of any
+// size. It holds at most one chunk of the file in memory.
+type EntryWriter struct {
+	name   string
+	chunks *CompactTree
+	// hashes holds the leaf hashes of the complete chunks.
+	hashes [][]byte
+	buf    []byte
+	size   uint64
+}
+
+// NewEntryWriter returns an EntryWriter for the file called name, hashing its
+// chunks with alg and mode.
+func NewEntryWriter(alg Algorithm, mode HashMode, name string) (*EntryWriter, error) {
+	h, err := lookupHasher(alg, mode)
+	if err != nil {
+		return nil, err
+	}
+	return &EntryWriter{
+		name:   name,
+		chunks: &CompactTree{hasher: h},
+		buf:    make([]byte, 0, ChunkSize),
+	}, nil
+}
+
+// Write hashes every chunk p completes. It never fails.
+func (w *EntryWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		k := ChunkSize - len(w.buf)
+		if k > len(p) {
+			k = len(p)
+		}
+		w.buf = append(w.buf, p[:k]...)
+		p = p[k:]
+		if len(w.buf) == ChunkSize {
+			hash := w.chunks.hasher.leaf(w.buf)
+			w.chunks.appendHash(hash)
+			w.hashes = append(w.hashes, hash)
+			w.buf = w.buf[:0]
+		}
+	}
+	w.size += uint64(n)
+	return n, nil
+}
+
+// Size returns the number of bytes written so far.
+func (w *EntryWriter) Size() uint64 {
+	return w.size
+}
+
+// Entry returns the entry of the file written so far. Writing can go on
+// afterwards.
+func (w *EntryWriter) Entry() Entry {
+	chunks := *w.chunks
+	chunks.frontier = append([][]byte(nil), w.chunks.frontier...)
+	// The last chunk is partial or, for an empty file, empty.
+	if len(w.buf) > 0 || w.size == 0 {
+		chunks.AddFile(w.buf)
+	}
+	root, _ := chunks.RootDigest()
+	return Entry{Name: w.name, Size: w.size, ContentHash: root.Hash}
+}
+
+// ChunkHashes returns the leaf hashes of the chunks of the file written so
+// far, from which NewChunkTreeFromHashes rebuilds its chunk tree.
+func (w *EntryWriter) ChunkHashes() [][]byte {
+	hashes := append([][]byte(nil), w.hashes...)
+	if len(w.buf) > 0 || w.size == 0 {
+		hashes = append(hashes, w.chunks.hasher.leaf(w.buf))
+	}
+	return hashes
+}
+
+// AddEntry adds a named file as a new leaf whose data is its encoded Entry.
+func (mt *MerkleTree) AddEntry(name string, content []byte) error {
+	return mt.AddFile(mt.hasher.entry(name, content).Encode())
+}
+
+// GetIndicesFromEntry returns, in ascending order, the index of every leaf
+// holding the named file with the given content.
+func (mt *MerkleTree) GetIndicesFromEntry(name string, content []byte) []int {
+	return mt.GetIndicesFromContent(mt.hasher.entry(name, content).Encode())
+}
+
+// AddEntry adds a named file as a new leaf whose data is its encoded Entry.
+func (ct *CompactTree) AddEntry(name string, content []byte) {
+	ct.AddFile(ct.hasher.entry(name, content).Encode())
+}
+
+// VerifyEntryProof checks that the file called name with the given content is
+// stored at proof.LeafIndex in the tree whose root is root.
+func VerifyEntryProof(name string, content []byte, proof *Proof, root Digest) error {
+	if proof == nil {
+		return errors.New("missing proof")
+	}
+	entry, err := NewEntry(proof.Algorithm, proof.Mode, name, content)
+	if err != nil {
+		return err
+	}
+	return VerifyProof(entry.Encode(), proof, root)
+}
@@ -0,0 +1,113 @@
+package merkle
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEntryEncodeDecode(t *testing.T) {
+	entry, err := NewEntry(SHA256, HashModeRFC6962, "report.pdf", []byte("content"))
+	if err != nil {
+		t.Fatalf("Failed to create entry: %v", err)
+	}
+	if entry.Size != 7 || len(entry.ContentHash) != 32 {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+
+	decoded, err := DecodeEntry(entry.Encode())
+	if err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if decoded.Name != entry.Name || decoded.Size != entry.Size || !bytes.Equal(decoded.ContentHash, entry.ContentHash) {
+		t.Errorf("Decoded entry %+v differs from %+v", decoded, entry)
+	}
+
+	if _, err := DecodeEntry([]byte{0, 0, 0, 9, 'a'}); err == nil {
+		t.Error("Truncated entry should not decode")
+	}
+}
+
+func TestAddEntry(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddEntry("a.txt", []byte("same"))
+	mt.AddEntry("b.txt", []byte("same"))
+	mt.AddEntry("c.txt", []byte("other"))
+	root, _ := mt.RootDigest()
+
+	if bytes.Equal(mt.Leaves[0].Hash, mt.Leaves[1].Hash) {
+		t.Error("Files with the same content but different names should have different leaves")
+	}
+	if indices := mt.GetIndicesFromEntry("b.txt", []byte("same")); len(indices) != 1 || indices[0] != 1 {
+		t.Errorf("Expected b.txt at index 1, got %v", indices)
+	}
+
+	proof, err := mt.GenerateProof(1)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if err := VerifyEntryProof("b.txt", []byte("same"), proof, root); err != nil {
+		t.Errorf("Entry proof did not verify: %v", err)
+	}
+	if err := VerifyEntryProof("a.txt", []byte("same"), proof, root); err == nil {
+		t.Error("Entry proof should not verify for another name")
+	}
+	if err := VerifyEntryProof("b.txt", []byte("other"), proof, root); err == nil {
+		t.Error("Entry proof should not verify for other content")
+	}
+
+	ct := NewCompactTree(WithHashMode(HashModeRFC6962))
+	ct.AddEntry("a.txt", []byte("same"))
+	ct.AddEntry("b.txt", []byte("same"))
+	ct.AddEntry("c.txt", []byte("other"))
+	if compactRoot, _ := ct.RootDigest(); !bytes.Equal(compactRoot.Hash, root.Hash) {
+		t.Error("CompactTree entries should produce the same root")
+	}
+}
+
+func TestEntryWriterMatchesEntry(t *testing.T) {
+	for _, size := range []int{0, 10, ChunkSize, ChunkSize + 1, 2*ChunkSize + 5} {
+		content := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+		want, _ := NewEntry(SHA256, HashModeRFC6962, "file", content)
+
+		// Write in pieces that straddle the chunk boundaries.
+		w, err := NewEntryWriter(SHA256, HashModeRFC6962, "file")
+		if err != nil {
+			t.Fatalf("Failed to create entry writer: %v", err)
+		}
+		for rest := content; len(rest) > 0; {
+			n := 3*ChunkSize/4 + 1
+			if n > len(rest) {
+				n = len(rest)
+			}
+			w.Write(rest[:n])
+			rest = rest[n:]
+		}
+		got := w.Entry()
+		if !bytes.Equal(got.Encode(), want.Encode()) || w.Size() != uint64(size) {
+			t.Errorf("Entry writer disagrees with NewEntry for %d bytes", size)
+		}
+		if again := w.Entry(); !bytes.Equal(again.Encode(), got.Encode()) {
+			t.Errorf("Entry changed the writer's state for %d bytes", size)
+		}
+
+		// The chunk hashes rebuild the chunk tree without the content.
+		opts := []Option{WithHashMode(HashModeRFC6962)}
+		read, _ := NewChunkTree(bytes.NewReader(content), opts...)
+		rebuilt, err := NewChunkTreeFromHashes(uint64(size), w.ChunkHashes(), opts...)
+		if err != nil {
+			t.Fatalf("Failed to rebuild chunk tree of %d bytes: %v", size, err)
+		}
+		if !bytes.Equal(rebuilt.Entry("file").Encode(), want.Encode()) || !reflect.DeepEqual(rebuilt.ChunkHashes(), read.ChunkHashes()) {
+			t.Errorf("Chunk tree rebuilt from hashes differs for %d bytes", size)
+		}
+		last := rebuilt.NumChunks() - 1
+		wantProof, _ := read.GenerateProof(last)
+		if got, _ := rebuilt.GenerateProof(last); !equalProofs(got, wantProof) {
+			t.Errorf("Rebuilt chunk tree proves chunk %d differently for %d bytes", last, size)
+		}
+		if _, err := NewChunkTreeFromHashes(uint64(size)+2*ChunkSize, w.ChunkHashes(), opts...); err == nil {
+			t.Errorf("Rebuilt a chunk tree from too few hashes")
+		}
+	}
+}
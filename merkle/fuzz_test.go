@@ -0,0 +1,500 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// maxFuzzLeaves bounds the trees built from fuzz input, which are proven leaf
+// by leaf.
+const maxFuzzLeaves = 64
+
+// fuzzLeaves splits data into leaves, each led by a byte giving its length
+// modulo 4. The short leaves make duplicates common.
+func fuzzLeaves(data []byte) [][]byte {
+	var leaves [][]byte
+	for len(data) > 0 && len(leaves) < maxFuzzLeaves {
+		n := int(data[0] % 4)
+		data = data[1:]
+		if n > len(data) {
+			n = len(data)
+		}
+		leaves = append(leaves, data[:n])
+		data = data[n:]
+	}
+	return leaves
+}
+
+// tampered returns a copy of b with one bit flipped.
+func tampered(b []byte, bit int) []byte {
+	c := append([]byte(nil), b...)
+	if len(c) == 0 {
+		return []byte{0}
+	}
+	c[bit/8%len(c)] ^= 1 << (bit % 8)
+	return c
+}
+
+// checkTreeProperties builds trees of leaves every way the package allows and
+// checks that they agree, that every proof verifies, and that tampered proofs
+// do not. It drives the verifiers the client uses the way the client does:
+// entry proofs against a CompactTree root, multiproofs, consistency proofs and
+// chunk proofs.
+func checkTreeProperties(t *testing.T, leaves [][]byte, opts ...Option) {
+	t.Helper()
+	batch := NewMerkleTree(opts...)
+	batch.AddLeaves(leaves)
+	incremental := NewMerkleTree(opts...)
+	compact := NewCompactTree(opts...)
+	for _, leaf := range leaves {
+		if err := incremental.AddFile(leaf); err != nil {
+			t.Fatal(err)
+		}
+		compact.AddFile(leaf)
+	}
+
+	root, err := batch.RootDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tree := range map[string]interface {
+		RootDigest() (Digest, error)
+	}{"incremental": incremental, "compact": compact} {
+		other, err := tree.RootDigest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(other.Hash, root.Hash) || other.Shape != root.Shape {
+			t.Fatalf("%s root of %d leaves differs from the batch root", name, len(leaves))
+		}
+	}
+	var mmr *MountainRange
+	if root.Shape == ShapePromote {
+		mmr = NewMountainRange(opts...)
+		for _, leaf := range leaves {
+			mmr.AddFile(leaf)
+		}
+		other, _ := mmr.RootDigest()
+		if !bytes.Equal(other.Hash, root.Hash) {
+			t.Fatalf("mountain range root of %d leaves differs from the batch root", len(leaves))
+		}
+	}
+
+	for i, leaf := range leaves {
+		proof, err := batch.GenerateProof(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyProof(leaf, proof, root); err != nil {
+			t.Fatalf("proof of leaf %d of %d did not verify: %v", i, len(leaves), err)
+		}
+		if other, _ := incremental.GenerateProof(i); !equalProofs(other, proof) {
+			t.Fatalf("incremental proof of leaf %d of %d differs", i, len(leaves))
+		}
+		if mmr != nil {
+			other, _ := mmr.GenerateProof(i)
+			if err := VerifyProof(leaf, other, root); err != nil {
+				t.Fatalf("mountain range proof of leaf %d of %d did not verify: %v", i, len(leaves), err)
+			}
+		}
+		checkTamperedProofs(t, leaves, proof, root)
+	}
+
+	// Entry proofs, as checked by the client.
+	entries := NewMerkleTree(opts...)
+	trusted := NewCompactTree(opts...)
+	for i, leaf := range leaves {
+		entries.AddEntry(fmt.Sprint(i), leaf)
+		trusted.AddEntry(fmt.Sprint(i), leaf)
+	}
+	trustedRoot, _ := trusted.RootDigest()
+	for i, leaf := range leaves {
+		proof, err := entries.GenerateProof(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyEntryProof(fmt.Sprint(i), leaf, proof, trustedRoot); err != nil {
+			t.Fatalf("entry proof of leaf %d of %d did not verify: %v", i, len(leaves), err)
+		}
+		if err := VerifyEntryProof(fmt.Sprint(i+1), leaf, proof, trustedRoot); err == nil {
+			t.Fatalf("entry proof of leaf %d of %d verified under another name", i, len(leaves))
+		}
+	}
+
+	// Multiproofs of every other leaf, and consistency proofs from every
+	// older size.
+	var indices []int
+	var proven [][]byte
+	for i := len(leaves) - 1; i >= 0; i -= 2 {
+		indices = append(indices, i)
+		proven = append(proven, leaves[i])
+	}
+	multi, err := batch.GenerateMultiProof(indices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyMultiProof(proven, multi, root); err != nil {
+		t.Fatalf("multiproof of %v in %d leaves did not verify: %v", indices, len(leaves), err)
+	}
+	proven[0] = tampered(proven[0], 0)
+	if err := VerifyMultiProof(proven, multi, root); err == nil {
+		t.Fatalf("tampered multiproof of %v in %d leaves verified", indices, len(leaves))
+	}
+	for oldSize := 1; oldSize <= len(leaves); oldSize++ {
+		oldRoot, _ := batch.RootAt(oldSize)
+		proof, err := batch.ConsistencyProof(oldSize, len(leaves))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyConsistency(proof, oldRoot, root); err != nil {
+			t.Fatalf("consistency proof from %d to %d did not verify: %v", oldSize, len(leaves), err)
+		}
+		for i := range proof.Hashes {
+			bad := *proof
+			bad.Hashes = append([][]byte(nil), proof.Hashes...)
+			bad.Hashes[i] = tampered(bad.Hashes[i], i)
+			if err := VerifyConsistency(&bad, oldRoot, root); err == nil {
+				t.Fatalf("tampered consistency proof from %d to %d verified", oldSize, len(leaves))
+			}
+		}
+	}
+
+	checkChunkProofs(t, leaves, opts...)
+}
+
+// checkChunkProofs stores a file among the entries of leaves and checks that
+// each of its chunks verifies against the global root, and that no change to
+// a chunk, its proof or the file's entry does. The file is the leaves joined,
+// after a full chunk of zeros for one tree size in four, so that both single
+// and multi-chunk files are proven.
+func checkChunkProofs(t *testing.T, leaves [][]byte, opts ...Option) {
+	t.Helper()
+	var content []byte
+	if len(leaves)%4 == 0 {
+		content = make([]byte, ChunkSize)
+	}
+	content = append(content, bytes.Join(leaves, nil)...)
+	ct, err := NewChunkTree(bytes.NewReader(content), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := ct.Entry("file")
+
+	mt := NewMerkleTree(opts...)
+	at := len(leaves) / 2
+	for i, leaf := range leaves {
+		if i == at {
+			mt.AddLeaves([][]byte{entry.Encode()})
+		}
+		mt.AddEntry(fmt.Sprint(i), leaf)
+	}
+	root, _ := mt.RootDigest()
+	fileProof, err := mt.GenerateProof(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := ct.NumChunks() - 1
+	for i := 0; i <= last; i++ {
+		chunk, _ := Chunk(content, i)
+		chunkProof, err := ct.GenerateProof(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof := &ChunkProof{Entry: entry, Chunk: chunkProof, File: fileProof}
+		if err := VerifyChunkProof(chunk, proof, root); err != nil {
+			t.Fatalf("chunk %d of %d did not verify: %v", i, last+1, err)
+		}
+		if i != last {
+			// Every check below hashes the chunk again; the short last chunk
+			// keeps them cheap.
+			continue
+		}
+
+		if err := VerifyChunkProof(tampered(chunk, i), proof, root); err == nil {
+			t.Fatalf("tampered chunk %d of %d verified", i, last+1)
+		}
+		if err := VerifyChunkProof(append(bytes.Clone(chunk), 0), proof, root); err == nil {
+			t.Fatalf("extended chunk %d of %d verified", i, last+1)
+		}
+		for k := range chunkProof.Siblings {
+			bad := *chunkProof
+			bad.Siblings = append([][]byte(nil), chunkProof.Siblings...)
+			bad.Siblings[k] = tampered(bad.Siblings[k], k)
+			if err := VerifyChunkProof(chunk, &ChunkProof{Entry: entry, Chunk: &bad, File: fileProof}, root); err == nil {
+				t.Fatalf("chunk %d of %d verified with sibling %d changed", i, last+1, k)
+			}
+		}
+		for k := range fileProof.Siblings {
+			bad := *fileProof
+			bad.Siblings = append([][]byte(nil), fileProof.Siblings...)
+			bad.Siblings[k] = tampered(bad.Siblings[k], k)
+			if err := VerifyChunkProof(chunk, &ChunkProof{Entry: entry, Chunk: chunkProof, File: &bad}, root); err == nil {
+				t.Fatalf("chunk %d of %d verified with file sibling %d changed", i, last+1, k)
+			}
+		}
+
+		bad := entry
+		bad.ContentHash = tampered(entry.ContentHash, i)
+		if err := VerifyChunkProof(chunk, &ChunkProof{Entry: bad, Chunk: chunkProof, File: fileProof}, root); err == nil {
+			t.Fatalf("chunk %d of %d verified with another content hash", i, last+1)
+		}
+		bad = entry
+		bad.Size++
+		if err := VerifyChunkProof(chunk, &ChunkProof{Entry: bad, Chunk: chunkProof, File: fileProof}, root); err == nil {
+			t.Fatalf("chunk %d of %d verified with another file size", i, last+1)
+		}
+		bad = entry
+		bad.Name = "other"
+		if err := VerifyChunkProof(chunk, &ChunkProof{Entry: bad, Chunk: chunkProof, File: fileProof}, root); err == nil {
+			t.Fatalf("chunk %d of %d verified under another name", i, last+1)
+		}
+	}
+}
+
+// maxSparseNames bounds the names FuzzSparseProofs adds: every change to a
+// sparse tree and every proof hashes a path as long as the key.
+const maxSparseNames = 8
+
+// checkSparseProofs names every leaf in a sparse tree, removes every third
+// name, and checks the proofs of presence of the names kept and of absence of
+// the names removed and of one never added. Changes are tried on the proofs
+// of the first name kept and of the one never added.
+func checkSparseProofs(t *testing.T, leaves [][]byte, opts ...Option) {
+	t.Helper()
+	smt := NewSparseMerkleTree(opts...)
+	for i, leaf := range leaves {
+		smt.Set(fmt.Sprint(i), leaf)
+	}
+	for i := 0; i < len(leaves); i += 3 {
+		smt.Delete(fmt.Sprint(i))
+	}
+	root := smt.RootDigest()
+
+	for i := 0; i <= len(leaves); i++ {
+		name := fmt.Sprint(i)
+		var value []byte
+		if i < len(leaves) && i%3 != 0 {
+			value = leaves[i]
+		}
+		proof := smt.GenerateProof(name)
+		if err := VerifySparseProof(name, value, proof, root); err != nil {
+			t.Fatalf("sparse proof of %q (value %x) did not verify: %v", name, value, err)
+		}
+		if i != 1 && i != len(leaves) {
+			continue
+		}
+
+		if value != nil {
+			if err := VerifySparseProof(name, tampered(value, i), proof, root); err == nil {
+				t.Fatalf("sparse proof of %q verified for another value", name)
+			}
+			if err := VerifySparseProof(name, nil, proof, root); err == nil {
+				t.Fatalf("present name %q was proven absent", name)
+			}
+		} else if err := VerifySparseProof(name, []byte{}, proof, root); err == nil {
+			t.Fatalf("absent name %q was proven present", name)
+		}
+		if err := VerifySparseProof(fmt.Sprint(i+1), value, proof, root); err == nil {
+			t.Fatalf("sparse proof of %q verified for another name", name)
+		}
+		for k := range proof.Siblings {
+			bad := *proof
+			bad.Siblings = append([][]byte(nil), proof.Siblings...)
+			bad.Siblings[k] = tampered(bad.Siblings[k], k)
+			if err := VerifySparseProof(name, value, &bad, root); err == nil {
+				t.Fatalf("sparse proof of %q verified with sibling %d changed", name, k)
+			}
+		}
+		for _, h := range []int{0, len(proof.Bitmap)*8 - 1} {
+			bad := *proof
+			bad.Bitmap = bytes.Clone(proof.Bitmap)
+			bad.Bitmap[h/8] ^= 1 << (h % 8)
+			if err := VerifySparseProof(name, value, &bad, root); err == nil {
+				t.Fatalf("sparse proof of %q verified with bit %d of its bitmap flipped", name, h)
+			}
+		}
+	}
+}
+
+// checkTamperedProofs checks that no single change to a valid proof of
+// leaves[proof.LeafIndex] verifies.
+func checkTamperedProofs(t *testing.T, leaves [][]byte, proof *Proof, root Digest) {
+	t.Helper()
+	i := proof.LeafIndex
+	if err := VerifyProof(tampered(leaves[i], i), proof, root); err == nil {
+		t.Fatalf("proof of leaf %d of %d verified for other content", i, len(leaves))
+	}
+	for k := range proof.Siblings {
+		bad := *proof
+		bad.Siblings = append([][]byte(nil), proof.Siblings...)
+		bad.Siblings[k] = tampered(bad.Siblings[k], k)
+		if err := VerifyProof(leaves[i], &bad, root); err == nil {
+			t.Fatalf("proof of leaf %d of %d verified with sibling %d changed", i, len(leaves), k)
+		}
+
+		bad = *proof
+		bad.Left = append([]bool(nil), proof.Left...)
+		bad.Left[k] = !bad.Left[k]
+		if err := VerifyProof(leaves[i], &bad, root); err == nil {
+			t.Fatalf("proof of leaf %d of %d verified with sibling %d moved", i, len(leaves), k)
+		}
+	}
+	if len(leaves) > 1 {
+		bad := *proof
+		bad.LeafIndex = (i + 1) % len(leaves)
+		if err := VerifyProof(leaves[i], &bad, root); err == nil {
+			t.Fatalf("proof of leaf %d of %d verified at index %d", i, len(leaves), bad.LeafIndex)
+		}
+	}
+	bad := *proof
+	bad.Shape ^= 1
+	if err := VerifyProof(leaves[i], &bad, root); err == nil {
+		t.Fatalf("proof of leaf %d of %d verified for another shape", i, len(leaves))
+	}
+}
+
+// equalProofs reports whether two proofs are identical.
+func equalProofs(a, b *Proof) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Algorithm != b.Algorithm || a.Mode != b.Mode || a.Shape != b.Shape || a.LeafIndex != b.LeafIndex ||
+		a.TreeSize != b.TreeSize || len(a.Siblings) != len(b.Siblings) {
+		return false
+	}
+	for k := range a.Siblings {
+		if !bytes.Equal(a.Siblings[k], b.Siblings[k]) || a.Left[k] != b.Left[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzOptions returns the tree options selected by the fuzzer.
+func fuzzOptions(promote, rfc6962 bool) []Option {
+	opts := []Option{WithShape(ShapeDuplicate)}
+	if promote {
+		opts[0] = WithShape(ShapePromote)
+	}
+	if rfc6962 {
+		opts = append(opts, WithHashMode(HashModeRFC6962))
+	}
+	return opts
+}
+
+func FuzzTreeProperties(f *testing.F) {
+	f.Add([]byte{1, 'a'}, true, true)
+	f.Add([]byte{1, 'a', 1, 'a', 1, 'a'}, false, true)
+	f.Add([]byte{0, 0, 0, 0, 0}, true, false)
+	f.Add([]byte("\x03abc\x01d\x02ef\x01g\x00\x03xyz\x01a"), true, true)
+	f.Add(bytes.Repeat([]byte{1, 7}, 33), false, false)
+	f.Fuzz(func(t *testing.T, data []byte, promote, rfc6962 bool) {
+		leaves := fuzzLeaves(data)
+		if len(leaves) == 0 {
+			return
+		}
+		checkTreeProperties(t, leaves, fuzzOptions(promote, rfc6962)...)
+	})
+}
+
+// FuzzVerifyProof feeds arbitrary proofs to the verifier, which must neither
+// panic nor accept a proof that differs from the real one.
+func FuzzVerifyProof(f *testing.F) {
+	f.Add(0, 5, []byte("sibling"), uint8(0), true)
+	f.Add(4, 5, []byte{}, uint8(3), false)
+	f.Add(-1, 0, []byte{0}, uint8(255), true)
+	f.Fuzz(func(t *testing.T, leafIndex, treeSize int, sibling []byte, left uint8, promote bool) {
+		leaves := [][]byte{{0}, {1}, {2}, {3}, {4}}
+		mt := NewMerkleTree(fuzzOptions(promote, true)...)
+		mt.AddLeaves(leaves)
+		root, _ := mt.RootDigest()
+
+		proof := &Proof{
+			Algorithm: root.Algorithm,
+			Mode:      root.Mode,
+			Shape:     root.Shape,
+			LeafIndex: leafIndex,
+			TreeSize:  treeSize,
+		}
+		for k := 0; k < 3; k++ {
+			proof.Siblings = append(proof.Siblings, sibling)
+			proof.Left = append(proof.Left, left>>k&1 == 1)
+		}
+		if leafIndex < 0 || leafIndex >= len(leaves) {
+			VerifyProof([]byte{0}, proof, root)
+			return
+		}
+		real, _ := mt.GenerateProof(leafIndex)
+		if err := VerifyProof(leaves[leafIndex], proof, root); err == nil && !equalProofs(proof, real) {
+			t.Fatalf("forged proof of leaf %d verified", leafIndex)
+		}
+	})
+}
+
+func FuzzSparseProofs(f *testing.F) {
+	f.Add([]byte{1, 'a'}, true)
+	f.Add([]byte{0, 1, 'a', 1, 'a', 2, 'b', 'c'}, false)
+	f.Add([]byte("\x03abc\x01d\x02ef\x01g\x00\x03xyz\x01a"), true)
+	f.Add([]byte{}, false)
+	f.Fuzz(func(t *testing.T, data []byte, rfc6962 bool) {
+		leaves := fuzzLeaves(data)
+		if len(leaves) > maxSparseNames {
+			leaves = leaves[:maxSparseNames]
+		}
+		checkSparseProofs(t, leaves, fuzzOptions(false, rfc6962)...)
+	})
+}
+
+func TestTreeProperties(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 300; iter++ {
+		size := 1 + rng.Intn(40)
+		if iter < 40 {
+			size = 1 + iter
+		}
+		var leaves [][]byte
+		for i := 0; i < size; i++ {
+			// Draw from a small alphabet so that many leaves repeat.
+			leaves = append(leaves, []byte{byte(rng.Intn(size/2 + 1))})
+		}
+		promote, rfc6962 := iter%2 == 0, iter%4 < 2
+		t.Run(fmt.Sprintf("%d-leaves-%v-%v", size, promote, rfc6962), func(t *testing.T) {
+			checkTreeProperties(t, leaves, fuzzOptions(promote, rfc6962)...)
+		})
+	}
+}
+
+// FuzzUnmarshalBinary feeds arbitrary tree states to the decoder, which must
+// reject them rather than panic, and must load whatever it accepts into a
+// tree that encodes back to an equivalent state.
+func FuzzUnmarshalBinary(f *testing.F) {
+	for _, opts := range [][]Option{fuzzOptions(true, true), fuzzOptions(false, false)} {
+		mt := NewMerkleTree(opts...)
+		mt.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+		data, _ := mt.MarshalBinary()
+		f.Add(data)
+	}
+	f.Add([]byte{TreeStateVersion, 0, 0, 0, 1, 0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var mt MerkleTree
+		if err := mt.UnmarshalBinary(data); err != nil {
+			return
+		}
+		encoded, err := mt.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var reloaded MerkleTree
+		if err := reloaded.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("re-encoded tree state did not load: %v", err)
+		}
+		a, _ := mt.RootDigest()
+		b, _ := reloaded.RootDigest()
+		if !bytes.Equal(a.Hash, b.Hash) || reloaded.Size() != mt.Size() {
+			t.Fatal("re-encoded tree state differs")
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sync"
+)
+
+// Algorithm identifies the hash function a tree is built with. It is recorded
+// in proofs and digests so a verifier cannot mix algorithms by mistake.
+type Algorithm string
+
+const (
+	SHA256     Algorithm = "sha256"
+	SHA512_256 Algorithm = "sha512/256"
+	SHA512     Algorithm = "sha512"
+)
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[Algorithm]func() hash.Hash{
+		SHA256:     sha256.New,
+		SHA512_256: sha512.New512_256,
+		SHA512:     sha512.New,
+	}
+)
+
+// RegisterAlgorithm makes a hash function available under alg, so that
+// VerifyProof can check proofs from trees built with WithHash(alg, newHash)
+// in processes that never build such a tree themselves. Registering alg again
+// with the same hash function does nothing; it panics if alg is registered to
+// another one, such as a built-in algorithm.
+func RegisterAlgorithm(alg Algorithm, newHash func() hash.Hash) {
+	if err := claimAlgorithm(alg, newHash); err != nil {
+		panic(err)
+	}
+}
+
+// claimAlgorithm registers newHash under alg unless alg is registered
+// already, in which case the registered function must agree with newHash.
+func claimAlgorithm(alg Algorithm, newHash func() hash.Hash) error {
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	registered, ok := algorithms[alg]
+	if !ok {
+		algorithms[alg] = newHash
+		return nil
+	}
+	// Functions cannot be compared, so compare what they compute.
+	probe := []byte("merkle hash algorithm probe")
+	a, b := registered(), newHash()
+	a.Write(probe)
+	b.Write(probe)
+	if !bytes.Equal(a.Sum(nil), b.Sum(nil)) {
+		return fmt.Errorf("hash algorithm %q is registered to another hash function", alg)
+	}
+	return nil
+}
+
+// LookupAlgorithm returns the hash function registered under alg.
+func LookupAlgorithm(alg Algorithm) (func() hash.Hash, bool) {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	newHash, ok := algorithms[alg]
+	return newHash, ok
+}
+
+// HashMode selects how leaves and interior nodes are hashed.
+type HashMode uint8
+
+const (
+	// HashModeLegacy hashes leaves and interior nodes without any prefix,
+	// children in position order, as trees did before domain separation
+	// was added.
+	HashModeLegacy HashMode = iota
+	// HashModeRFC6962 prefixes leaf data with 0x00 and interior nodes with
+	// 0x01, as in RFC 6962, so an interior node cannot be passed off as a leaf.
+	HashModeRFC6962
+	// HashModeSorted hashes without any prefix and sorts the two children of
+	// a node before hashing them, as the first trees did; with ShapeDuplicate
+	// it reproduces their roots. A proof then no longer binds a leaf to its
+	// position, so it is only meant for verifying those trees.
+	HashModeSorted
+)
+
+const (
+	leafPrefix     = 0x00
+	interiorPrefix = 0x01
+)
+
+func (m HashMode) String() string {
+	switch m {
+	case HashModeLegacy:
+		return "legacy"
+	case HashModeRFC6962:
+		return "rfc6962"
+	case HashModeSorted:
+		return "sorted"
+	default:
+		return fmt.Sprintf("HashMode(%d)", uint8(m))
+	}
+}
+
+func (m HashMode) valid() bool {
+	return m == HashModeLegacy || m == HashModeRFC6962 || m == HashModeSorted
+}
+
+// hasher computes leaf and interior hashes for one algorithm and mode.
+type hasher struct {
+	alg     Algorithm
+	newHash func() hash.Hash
+	mode    HashMode
+}
+
+var defaultHasher = hasher{alg: SHA256, newHash: sha256.New, mode: HashModeLegacy}
+
+// lookupHasher returns the hasher for a registered algorithm.
+func lookupHasher(alg Algorithm, mode HashMode) (hasher, error) {
+	newHash, ok := LookupAlgorithm(alg)
+	if !ok {
+		return hasher{}, fmt.Errorf("unknown hash algorithm %q", alg)
+	}
+	if !mode.valid() {
+		return hasher{}, fmt.Errorf("unknown hash mode %v", mode)
+	}
+	return hasher{alg: alg, newHash: newHash, mode: mode}, nil
+}
+
+// leaf returns the hash of a leaf from its data.
+func (h hasher) leaf(data []byte) []byte {
+	d := h.newHash()
+	if h.mode == HashModeRFC6962 {
+		d.Write([]byte{leafPrefix})
+	}
+	d.Write(data)
+	return d.Sum(nil)
+}
+
+// children returns the hash of an interior node from its children.
+func (h hasher) children(left, right []byte) []byte {
+	d := h.newHash()
+	if h.mode == HashModeRFC6962 {
+		d.Write([]byte{interiorPrefix})
+	}
+	if h.mode == HashModeSorted && bytes.Compare(left, right) > 0 {
+		left, right = right, left
+	}
+	d.Write(left)
+	d.Write(right)
+	return d.Sum(nil)
+}
+
+// positional returns h with HashModeSorted replaced by HashModeLegacy, for
+// trees whose proofs must bind a key to its position.
+func (h hasher) positional() hasher {
+	if h.mode == HashModeSorted {
+		h.mode = HashModeLegacy
+	}
+	return h
+}
+
+// tombstone returns the hash that marks a removed leaf.
+func (h hasher) tombstone() []byte {
+	return make([]byte, h.newHash().Size())
+}
@@ -2,9 +2,12 @@ package merkle
 
 import (
 	"bytes"
-	"crypto/sha256"
 	"errors"
 	"fmt"
+	"hash"
+	"math/bits"
+	"sort"
+	"sync"
 )
 
 type Node struct {
@@ -14,108 +17,464 @@ type Node struct {
 	Parent *Node
 }
 
+// MerkleTree is safe for concurrent use through its methods. Root and Leaves
+// must not be read while the tree may be modified; use RootDigest and Size.
 type MerkleTree struct {
 	Root   *Node
 	Leaves []*Node
+	hasher hasher
+	shape  Shape
+	// mu guards every field. Hashes are never modified in place, so the
+	// slices a reader returns stay valid after it unlocks.
+	mu sync.RWMutex
+	// levels holds every level of the tree, levels[0] being the leaves.
+	levels [][]*Node
+	// index maps a leaf hash to the sorted positions of every leaf with
+	// that hash.
+	index map[string][]int
+	// hashes holds the leaf hashes, shared with the snapshots.
+	hashes leafHashes
+	// current is the snapshot of the tree as it is now, and history the
+	// last retain snapshots, oldest first.
+	current *Snapshot
+	history []*Snapshot
+	retain  int
+	// levelCache bounds how many snapshots keep their levels built.
+	levelCache *levelCache
+	// workers is the number of goroutines hashing batches of leaves and
+	// levels, or 0 for runtime.GOMAXPROCS.
+	workers int
+}
+
+// Digest is a root hash tagged with the algorithm, mode and shape of the tree
+// that produced it.
+type Digest struct {
+	Algorithm Algorithm
+	Mode      HashMode
+	Shape     Shape
+	Hash      []byte
+}
+
+// config holds the settings shared by every tree type in this package.
+type config struct {
+	hasher    hasher
+	snapshots int
+	workers   int
+	shape     Shape
+}
+
+// Option configures a tree at construction time.
+type Option func(*config)
+
+// WithHashMode selects how leaves and interior nodes are hashed. Trees use
+// HashModeLegacy unless told otherwise, so that roots built before domain
+// separation was added can be reproduced.
+func WithHashMode(mode HashMode) Option {
+	return func(c *config) {
+		c.hasher.mode = mode
+	}
+}
+
+// WithHash builds the tree with newHash and records alg in its proofs and
+// digests. Trees use SHA256 unless told otherwise. Verifiers resolve alg
+// through RegisterAlgorithm, which already knows SHA256, SHA512_256 and SHA512;
+// WithHash registers newHash under alg if it is new, and panics if alg is
+// registered to another hash function, since verifiers would then check the
+// tree's proofs with the wrong one.
+func WithHash(alg Algorithm, newHash func() hash.Hash) Option {
+	if err := claimAlgorithm(alg, newHash); err != nil {
+		panic(err)
+	}
+	return func(c *config) {
+		c.hasher.alg = alg
+		c.hasher.newHash = newHash
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{hasher: defaultHasher, snapshots: DefaultSnapshots, shape: DefaultShape}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
 }
 
 // NewMerkleTree creates a new MerkleTree.
-func NewMerkleTree() *MerkleTree {
+func NewMerkleTree(opts ...Option) *MerkleTree {
+	c := newConfig(opts)
 	return &MerkleTree{
-		Root:   nil,
-		Leaves: []*Node{},
+		Root:    nil,
+		Leaves:  []*Node{},
+		hasher:  c.hasher,
+		shape:   c.shape,
+		index:   make(map[string][]int),
+		retain:  c.snapshots,
+		workers: c.workers,
 	}
 }
 
+// Mode returns the hashing mode the tree was built with.
+func (mt *MerkleTree) Mode() HashMode {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.hasher.mode
+}
+
+// Algorithm returns the hash algorithm the tree was built with.
+func (mt *MerkleTree) Algorithm() Algorithm {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.hasher.alg
+}
+
+// Shape returns the shape the tree was built with.
+func (mt *MerkleTree) Shape() Shape {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.shape
+}
+
+// Size returns the number of leaves in the tree, removed leaves included.
+func (mt *MerkleTree) Size() int {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return len(mt.Leaves)
+}
+
 func (mt *MerkleTree) AddLeaves(leaves [][]byte) {
-	for _, leaf := range leaves {
-		hash := sha256.Sum256(leaf)
-		newLeaf := &Node{
-			Hash: hash[:],
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	newLeaves := make([]*Node, len(leaves))
+	parallelFor(len(leaves), mt.workers, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			newLeaves[i] = &Node{
+				Hash: mt.hasher.leaf(leaves[i]),
+			}
 		}
-		mt.Leaves = append(mt.Leaves, newLeaf)
-	}
+	})
+	mt.Leaves = append(mt.Leaves, newLeaves...)
 	mt.recalculateTree()
+	mt.recordSnapshot()
 }
 
-// AddFile adds a new file (as a leaf node) and re-calculates the tree.
+// AddFile adds a new file (as a leaf node) and updates the tree. Only the
+// right edge of the tree changes, so this costs O(log n) hashes.
 func (mt *MerkleTree) AddFile(fileContent []byte) error {
-	hash := sha256.Sum256(fileContent)
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
 	newLeaf := &Node{
-		Hash: hash[:],
+		Hash: mt.hasher.leaf(fileContent),
 	}
 
 	mt.Leaves = append(mt.Leaves, newLeaf)
-	err := mt.recalculateTree()
+	err := mt.updateRightEdge()
 	if err != nil {
-		return fmt.Errorf("recalculate tree error: %v", err)
+		return fmt.Errorf("update tree error: %v", err)
 	}
+	mt.recordSnapshot()
 	return nil
 
 }
 
+// UpdateLeaf replaces the content of the leaf at index and rehashes only the
+// path from that leaf to the root. Proofs generated before the update no
+// longer verify against the new root, but can still be generated from a
+// Snapshot of the old version.
+func (mt *MerkleTree) UpdateLeaf(index int, content []byte) error {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	if index < 0 || index >= len(mt.Leaves) {
+		return errors.New("invalid leaf index")
+	}
+	if mt.isRemoved(index) {
+		return errors.New("leaf has been removed")
+	}
+
+	leaf := mt.Leaves[index]
+	mt.unindexLeaf(index)
+	leaf.Hash = mt.hasher.leaf(content)
+	mt.indexLeaf(index)
+	mt.updatePath(leaf)
+	mt.setLeafHash(index)
+	mt.recordSnapshot()
+	return nil
+}
+
+// RemoveLeaf replaces the leaf at index with a tombstone and rehashes only the
+// path from that leaf to the root. The leaf keeps its position so the indices
+// of the other leaves do not change. The tombstone is an all-zero hash, which
+// no leaf content can produce, so a removed leaf cannot be proven.
+func (mt *MerkleTree) RemoveLeaf(index int) error {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	if index < 0 || index >= len(mt.Leaves) {
+		return errors.New("invalid leaf index")
+	}
+	if mt.isRemoved(index) {
+		return errors.New("leaf has been removed")
+	}
+
+	leaf := mt.Leaves[index]
+	mt.unindexLeaf(index)
+	leaf.Hash = mt.hasher.tombstone()
+	mt.updatePath(leaf)
+	mt.setLeafHash(index)
+	mt.recordSnapshot()
+	return nil
+}
+
+// IsRemoved reports whether the leaf at index has been removed.
+func (mt *MerkleTree) IsRemoved(index int) bool {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return index >= 0 && index < len(mt.Leaves) && mt.isRemoved(index)
+}
+
+func (mt *MerkleTree) isRemoved(index int) bool {
+	return bytes.Equal(mt.Leaves[index].Hash, mt.hasher.tombstone())
+}
+
+// updatePath rehashes the ancestors of a node whose hash has changed.
+func (mt *MerkleTree) updatePath(node *Node) {
+	for node = node.Parent; node != nil; node = node.Parent {
+		node.Hash = mt.hasher.children(node.Left.Hash, node.Right.Hash)
+	}
+}
+
+// recalculateTree rebuilds every level of the tree from the leaves.
 func (mt *MerkleTree) recalculateTree() error {
 	if len(mt.Leaves) == 0 {
 		return errors.New("no leaves to build tree")
 	}
 
+	mt.index = make(map[string][]int, len(mt.Leaves))
+	for i := range mt.Leaves {
+		if !mt.isRemoved(i) {
+			mt.indexLeaf(i)
+		}
+	}
+
 	nodes := mt.Leaves
+	mt.levels = [][]*Node{nodes}
 	for len(nodes) > 1 {
-		var nextLevel []*Node
+		nextLevel := mt.buildLevel(nodes)
+		mt.levels = append(mt.levels, nextLevel)
+		nodes = nextLevel
+	}
 
-		for i := 0; i < len(nodes); i += 2 {
-			// If we're at the end and there's an odd number of nodes, duplicate the last one.
-			if i+1 == len(nodes) {
-				nodes = append(nodes, nodes[i])
-			}
+	mt.Root = nodes[0]
+	return nil
+}
 
-			// Enforce comparison for ordering
-			first, second := nodes[i].Hash, nodes[i+1].Hash
-			if bytes.Compare(first, second) > 0 {
-				first, second = second, first
+// buildLevel returns the parents of a level of nodes, splitting the level
+// between the tree's workers when it is large.
+func (mt *MerkleTree) buildLevel(nodes []*Node) []*Node {
+	nextLevel := make([]*Node, (len(nodes)+1)/2)
+	parallelFor(len(nodes), mt.workers, func(lo, hi int) {
+		for i := lo; i < hi; i += 2 {
+			// If we're at the end and there's an odd number of nodes, promote
+			// or duplicate the last one.
+			right := nodes[i]
+			if i+1 < len(nodes) {
+				right = nodes[i+1]
+			} else if mt.shape == ShapePromote {
+				nodes[i].Parent = nil
+				nextLevel[i/2] = nodes[i]
+				continue
 			}
 
-			combinedHash := append(first, second...)
-			hash := sha256.Sum256(combinedHash)
-
+			// Children are hashed in position order so proofs bind the leaf index.
 			newNode := &Node{
-				Hash:  hash[:],
+				Hash:  mt.hasher.children(nodes[i].Hash, right.Hash),
 				Left:  nodes[i],
-				Right: nodes[i+1],
+				Right: right,
 			}
 			nodes[i].Parent = newNode
-			nodes[i+1].Parent = newNode
+			right.Parent = newNode
 
-			nextLevel = append(nextLevel, newNode)
+			nextLevel[i/2] = newNode
 		}
+	})
+	return nextLevel
+}
 
-		nodes = nextLevel
+// updateRightEdge brings the tree up to date after leaves were appended to
+// mt.Leaves. Appending only changes the last node of each level, so those
+// nodes are rehashed in place and a new level is added when the tree grows
+// taller. The result is identical to recalculateTree.
+func (mt *MerkleTree) updateRightEdge() error {
+	if len(mt.Leaves) == 0 {
+		return errors.New("no leaves to build tree")
+	}
+	if len(mt.levels) == 0 || len(mt.Leaves)-len(mt.levels[0]) != 1 {
+		// Only a single appended leaf can be absorbed incrementally.
+		return mt.recalculateTree()
 	}
 
-	mt.Root = nodes[0]
+	mt.indexLeaf(len(mt.Leaves) - 1)
+	mt.levels[0] = mt.Leaves
+	for l := 0; len(mt.levels[l]) > 1; l++ {
+		nodes := mt.levels[l]
+		if l+1 == len(mt.levels) {
+			mt.levels = append(mt.levels, nil)
+		}
+
+		// The last node of this level and its pair, or itself when the
+		// level has an odd number of nodes.
+		i := (len(nodes) - 1) &^ 1
+		left, right := nodes[i], nodes[i]
+		if i+1 < len(nodes) {
+			right = nodes[i+1]
+		}
+
+		var parent *Node
+		p := i / 2
+		switch {
+		case left == right && mt.shape == ShapePromote:
+			parent = left
+			parent.Parent = nil
+		case p < len(mt.levels[l+1]) && mt.levels[l+1][p] != left:
+			parent = mt.levels[l+1][p]
+		default:
+			// Either a new node, or one replacing the promoted left node.
+			parent = &Node{}
+		}
+		if parent != left {
+			parent.Hash = mt.hasher.children(left.Hash, right.Hash)
+			parent.Left = left
+			parent.Right = right
+			left.Parent = parent
+			right.Parent = parent
+		}
+		if p < len(mt.levels[l+1]) {
+			mt.levels[l+1][p] = parent
+		} else {
+			mt.levels[l+1] = append(mt.levels[l+1], parent)
+		}
+	}
+
+	mt.Root = mt.levels[len(mt.levels)-1][0]
 	return nil
 }
 
-// ComputeRoot returns the Merkle root.
+// ComputeRoot returns the Merkle root. The node belongs to the tree and is
+// rehashed in place as leaves are added; use RootDigest to read the root while
+// the tree may be modified.
 func (mt *MerkleTree) ComputeRoot() (*Node, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
 	if mt.Root == nil {
 		return nil, errors.New("merkle tree has not been calculated yet")
 	}
 	return mt.Root, nil
 }
 
-func (mt *MerkleTree) GenerateProof(leafIndex int) ([][]byte, error) {
+// RootDigest returns the Merkle root tagged with the tree's algorithm and mode.
+func (mt *MerkleTree) RootDigest() (Digest, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.rootDigest()
+}
+
+func (mt *MerkleTree) rootDigest() (Digest, error) {
+	if mt.Root == nil {
+		return Digest{}, errors.New("merkle tree has not been calculated yet")
+	}
+	return Digest{
+		Algorithm: mt.hasher.alg,
+		Mode:      mt.hasher.mode,
+		Shape:     mt.shape,
+		Hash:      mt.Root.Hash,
+	}, nil
+}
+
+// RootAt returns the root the tree had when it held its first size leaves,
+// computed from the current leaves.
+func (mt *MerkleTree) RootAt(size int) (Digest, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	if size <= 0 || size > len(mt.Leaves) {
+		return Digest{}, fmt.Errorf("invalid tree size %d", size)
+	}
+	return Digest{
+		Algorithm: mt.hasher.alg,
+		Mode:      mt.hasher.mode,
+		Shape:     mt.shape,
+		Hash:      mt.subtreeHash(0, size, height(size)),
+	}, nil
+}
+
+// subtreeHash returns the hash of the node at the given height whose leaves
+// are the count leaves starting at start, with count <= 2^height. Nodes that
+// exist in the current tree are looked up; the others, which only lie on the
+// right edge of a smaller tree, are recomputed.
+func (mt *MerkleTree) subtreeHash(start, count, h int) []byte {
+	i := start >> h
+	if end := (i + 1) << h; i < len(mt.levels[h]) && (count == 1<<h || start+count == len(mt.Leaves) && end >= len(mt.Leaves)) {
+		return mt.levels[h][i].Hash
+	}
+
+	k := 1 << (h - 1)
+	if count <= k {
+		return mt.hasher.unpaired(mt.subtreeHash(start, count, h-1), mt.shape)
+	}
+	return mt.hasher.children(mt.subtreeHash(start, k, h-1), mt.subtreeHash(start+k, count-k, h-1))
+}
+
+// height returns the height of a tree of size leaves.
+func height(size int) int {
+	return bits.Len(uint(size - 1))
+}
+
+// GenerateProof returns an inclusion proof for the leaf at leafIndex.
+func (mt *MerkleTree) GenerateProof(leafIndex int) (*Proof, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.generateProof(leafIndex)
+}
+
+// GenerateProofWithRoot returns an inclusion proof for the leaf at leafIndex
+// together with the root it verifies against. Both are read at once, so an
+// addition that lands in between cannot make them disagree.
+func (mt *MerkleTree) GenerateProofWithRoot(leafIndex int) (*Proof, Digest, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	proof, err := mt.generateProof(leafIndex)
+	if err != nil {
+		return nil, Digest{}, err
+	}
+	root, err := mt.rootDigest()
+	if err != nil {
+		return nil, Digest{}, err
+	}
+	return proof, root, nil
+}
+
+func (mt *MerkleTree) generateProof(leafIndex int) (*Proof, error) {
 	if leafIndex < 0 || leafIndex >= len(mt.Leaves) {
 		return nil, errors.New("invalid leaf index")
 	}
+	if mt.isRemoved(leafIndex) {
+		return nil, errors.New("leaf has been removed")
+	}
 
-	var proof [][]byte
+	proof := &Proof{
+		Algorithm: mt.hasher.alg,
+		Mode:      mt.hasher.mode,
+		Shape:     mt.shape,
+		LeafIndex: leafIndex,
+		TreeSize:  len(mt.Leaves),
+	}
 	current := mt.Leaves[leafIndex]
 
 	for current.Parent != nil {
 		sibling := getSibling(current)
 		if sibling != nil {
-			proof = append(proof, sibling.Hash)
+			proof.Siblings = append(proof.Siblings, sibling.Hash)
+			// A duplicated node is both children of its parent, so it is
+			// always treated as the left input.
+			proof.Left = append(proof.Left, current.Parent.Left != current)
 		}
 		current = current.Parent
 	}
@@ -135,17 +494,53 @@ func getSibling(node *Node) *Node {
 	}
 }
 
-// GetIndexFromContent returns the index of a leaf node given its content.
+// GetIndexFromContent returns the index of the first leaf node with the given
+// content, or -1 if there is none. Use GetIndicesFromContent when the same
+// content may have been added more than once.
 func (mt *MerkleTree) GetIndexFromContent(content []byte) int {
-	hash := sha256.Sum256(content)
-	return mt.getIndexFromHash(hash[:])
+	indices := mt.GetIndicesFromContent(content)
+	if len(indices) == 0 {
+		return -1
+	}
+	return indices[0]
 }
 
-func (mt *MerkleTree) getIndexFromHash(hash []byte) int {
-	for i, leaf := range mt.Leaves {
-		if bytes.Equal(leaf.Hash, hash) {
-			return i
-		}
+// GetIndicesFromContent returns, in ascending order, the index of every leaf
+// node with the given content.
+func (mt *MerkleTree) GetIndicesFromContent(content []byte) []int {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.getIndicesFromHash(mt.hasher.leaf(content))
+}
+
+func (mt *MerkleTree) getIndicesFromHash(hash []byte) []int {
+	indices := mt.index[string(hash)]
+	return append([]int(nil), indices...)
+}
+
+// indexLeaf records the leaf at position i under its current hash.
+func (mt *MerkleTree) indexLeaf(i int) {
+	key := string(mt.Leaves[i].Hash)
+	indices := mt.index[key]
+	pos := sort.SearchInts(indices, i)
+	indices = append(indices, 0)
+	copy(indices[pos+1:], indices[pos:])
+	indices[pos] = i
+	mt.index[key] = indices
+}
+
+// unindexLeaf forgets the leaf at position i under its current hash.
+func (mt *MerkleTree) unindexLeaf(i int) {
+	key := string(mt.Leaves[i].Hash)
+	indices := mt.index[key]
+	pos := sort.SearchInts(indices, i)
+	if pos == len(indices) || indices[pos] != i {
+		return
+	}
+	indices = append(indices[:pos], indices[pos+1:]...)
+	if len(indices) == 0 {
+		delete(mt.index, key)
+	} else {
+		mt.index[key] = indices
 	}
-	return -1
 }
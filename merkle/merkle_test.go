@@ -3,6 +3,8 @@ package merkle
 import (
 	"bytes"
 	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
 	"testing"
 )
 
@@ -67,7 +69,534 @@ func TestGenerateProof(t *testing.T) {
 		t.Fatalf("Failed to generate proof: %v", err)
 	}
 
-	if len(proof) == 0 {
+	if len(proof.Siblings) == 0 {
 		t.Error("Proof should not be empty")
 	}
-}
\ No newline at end of file
+	if proof.LeafIndex != 1 || proof.TreeSize != 4 {
+		t.Errorf("Unexpected proof position: index %d, size %d", proof.LeafIndex, proof.TreeSize)
+	}
+	root, _ := mt.RootDigest()
+	if err := VerifyProof(leaves[1], proof, root); err != nil {
+		t.Errorf("Proof did not verify: %v", err)
+	}
+}
+
+func TestVerifyProof(t *testing.T) {
+	for size := 1; size <= 9; size++ {
+		mt := NewMerkleTree()
+		var leaves [][]byte
+		for i := 0; i < size; i++ {
+			leaves = append(leaves, []byte{byte('a' + i)})
+		}
+		mt.AddLeaves(leaves)
+		root, _ := mt.RootDigest()
+
+		for i, leaf := range leaves {
+			proof, err := mt.GenerateProof(i)
+			if err != nil {
+				t.Fatalf("Failed to generate proof: %v", err)
+			}
+			if err := VerifyProof(leaf, proof, root); err != nil {
+				t.Errorf("Proof for leaf %d of %d did not verify: %v", i, size, err)
+			}
+		}
+	}
+}
+
+func TestVerifyProofRejectsTampering(t *testing.T) {
+	mt := NewMerkleTree()
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}, {'d'}, {'e'}}
+	mt.AddLeaves(leaves)
+	root, _ := mt.RootDigest()
+
+	proof, err := mt.GenerateProof(2)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+
+	if err := VerifyProof([]byte{'x'}, proof, root); err == nil {
+		t.Error("Proof should not verify for different content")
+	}
+
+	moved := *proof
+	moved.LeafIndex = 3
+	if err := VerifyProof(leaves[2], &moved, root); err == nil {
+		t.Error("Proof should not verify for a different leaf index")
+	}
+
+	flipped := *proof
+	flipped.Left = append([]bool{!proof.Left[0]}, proof.Left[1:]...)
+	if err := VerifyProof(leaves[2], &flipped, root); err == nil {
+		t.Error("Proof should not verify with flipped directions")
+	}
+
+	tampered := *proof
+	tampered.Siblings = append([][]byte{sha256.New().Sum(nil)}, proof.Siblings[1:]...)
+	if err := VerifyProof(leaves[2], &tampered, root); err == nil {
+		t.Error("Proof should not verify with a tampered sibling")
+	}
+}
+
+func TestHashModeRFC6962(t *testing.T) {
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}, {'d'}}
+	legacy := NewMerkleTree()
+	legacy.AddLeaves(leaves)
+	rfc := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	rfc.AddLeaves(leaves)
+
+	hash := sha256.Sum256([]byte{0x00, 'a'})
+	if !bytes.Equal(rfc.Leaves[0].Hash, hash[:]) {
+		t.Errorf("Leaf hash should be prefixed with 0x00")
+	}
+	if bytes.Equal(legacy.Root.Hash, rfc.Root.Hash) {
+		t.Errorf("Roots should differ between hash modes")
+	}
+
+	root, _ := rfc.RootDigest()
+	for i, leaf := range leaves {
+		proof, err := rfc.GenerateProof(i)
+		if err != nil {
+			t.Fatalf("Failed to generate proof: %v", err)
+		}
+		if proof.Mode != HashModeRFC6962 {
+			t.Errorf("Proof should record the tree's hash mode")
+		}
+		if err := VerifyProof(leaf, proof, root); err != nil {
+			t.Errorf("Proof for leaf %d did not verify: %v", i, err)
+		}
+		proof.Mode = HashModeLegacy
+		if err := VerifyProof(leaf, proof, root); err == nil {
+			t.Errorf("Proof for leaf %d should not verify in another mode", i)
+		}
+	}
+}
+
+func TestHashModeRFC6962PreventsSecondPreimage(t *testing.T) {
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}, {'d'}}
+	for _, mode := range []HashMode{HashModeLegacy, HashModeRFC6962} {
+		mt := NewMerkleTree(WithHashMode(mode))
+		mt.AddLeaves(leaves)
+
+		// Present the interior node over a and b as a leaf of a two-leaf tree.
+		forged := append(append([]byte{}, mt.Leaves[0].Hash...), mt.Leaves[1].Hash...)
+		proof := &Proof{
+			Algorithm: SHA256,
+			Mode:      mode,
+			Shape:     DefaultShape,
+			LeafIndex: 0,
+			TreeSize:  2,
+			Siblings:  [][]byte{mt.Root.Right.Hash},
+			Left:      []bool{false},
+		}
+		root, _ := mt.RootDigest()
+		err := VerifyProof(forged, proof, root)
+		if mode == HashModeLegacy && err != nil {
+			t.Errorf("Expected the legacy mode to accept the forged leaf: %v", err)
+		}
+		if mode == HashModeRFC6962 && err == nil {
+			t.Errorf("Forged leaf should not verify in %v mode", mode)
+		}
+	}
+}
+
+func TestWithHash(t *testing.T) {
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}}
+	for _, alg := range []Algorithm{SHA256, SHA512_256, SHA512} {
+		newHash, ok := LookupAlgorithm(alg)
+		if !ok {
+			t.Fatalf("Algorithm %q should be registered", alg)
+		}
+		mt := NewMerkleTree(WithHash(alg, newHash), WithHashMode(HashModeRFC6962))
+		mt.AddLeaves(leaves)
+
+		root, err := mt.RootDigest()
+		if err != nil {
+			t.Fatalf("Failed to compute root: %v", err)
+		}
+		if root.Algorithm != alg || len(root.Hash) != newHash().Size() {
+			t.Errorf("Root should be a %q digest, got %q with %d bytes", alg, root.Algorithm, len(root.Hash))
+		}
+
+		proof, err := mt.GenerateProof(2)
+		if err != nil {
+			t.Fatalf("Failed to generate proof: %v", err)
+		}
+		if proof.Algorithm != alg {
+			t.Errorf("Proof should record algorithm %q, got %q", alg, proof.Algorithm)
+		}
+		if err := VerifyProof(leaves[2], proof, root); err != nil {
+			t.Errorf("Proof did not verify with %q: %v", alg, err)
+		}
+
+		mixed := root
+		mixed.Algorithm = SHA256
+		if alg != SHA256 {
+			if err := VerifyProof(leaves[2], proof, mixed); err == nil {
+				t.Errorf("Proof from %q should not verify against a %q root", alg, SHA256)
+			}
+		}
+	}
+}
+
+func TestVerifyProofUnknownAlgorithm(t *testing.T) {
+	mt := NewMerkleTree()
+	mt.AddLeaves([][]byte{{'a'}, {'b'}})
+	root, _ := mt.RootDigest()
+	proof, _ := mt.GenerateProof(0)
+	alg := testAlgorithm(t)
+	root.Algorithm, proof.Algorithm = alg, alg
+
+	if err := VerifyProof([]byte{'a'}, proof, root); err == nil {
+		t.Error("Proof should not verify with an unregistered algorithm")
+	}
+
+	RegisterAlgorithm(alg, sha256.New)
+	if err := VerifyProof([]byte{'a'}, proof, root); err != nil {
+		t.Errorf("Proof should verify once the algorithm is registered: %v", err)
+	}
+	RegisterAlgorithm(alg, sha256.New)
+
+	for _, alg := range []Algorithm{SHA256, alg} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterAlgorithm should reject %q with another hash function", alg)
+				}
+			}()
+			RegisterAlgorithm(alg, sha512.New)
+		}()
+	}
+}
+
+// testAlgorithm returns the name of an algorithm no other test uses, which is
+// unregistered again once the test ends.
+func testAlgorithm(t *testing.T) Algorithm {
+	alg := Algorithm("test/" + t.Name())
+	t.Cleanup(func() {
+		algorithmsMu.Lock()
+		defer algorithmsMu.Unlock()
+		delete(algorithms, alg)
+	})
+	return alg
+}
+
+func TestWithHashRegistersAlgorithm(t *testing.T) {
+	custom := testAlgorithm(t)
+	mt := NewMerkleTree(WithHash(custom, sha256.New))
+	mt.AddLeaves([][]byte{{'a'}, {'b'}})
+	root, _ := mt.RootDigest()
+	proof, _ := mt.GenerateProof(0)
+	if err := VerifyProof([]byte{'a'}, proof, root); err != nil {
+		t.Errorf("Proof from a tree with a new algorithm should verify: %v", err)
+	}
+
+	for _, alg := range []Algorithm{SHA256, custom} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithHash should reject %q with another hash function", alg)
+				}
+			}()
+			WithHash(alg, sha512.New)
+		}()
+	}
+}
+
+func TestAddFileMatchesBatchBuild(t *testing.T) {
+	incremental := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	var leaves [][]byte
+	for size := 1; size <= 40; size++ {
+		leaf := []byte{byte(size)}
+		leaves = append(leaves, leaf)
+		if err := incremental.AddFile(leaf); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+
+		batch := NewMerkleTree(WithHashMode(HashModeRFC6962))
+		batch.AddLeaves(leaves)
+		if !bytes.Equal(incremental.Root.Hash, batch.Root.Hash) {
+			t.Fatalf("Incremental root differs from batch root at size %d", size)
+		}
+
+		root, _ := incremental.RootDigest()
+		for i, leaf := range leaves {
+			proof, err := incremental.GenerateProof(i)
+			if err != nil {
+				t.Fatalf("Failed to generate proof: %v", err)
+			}
+			if err := VerifyProof(leaf, proof, root); err != nil {
+				t.Errorf("Proof for leaf %d of %d did not verify: %v", i, size, err)
+			}
+		}
+	}
+}
+
+func BenchmarkAddFile(b *testing.B) {
+	mt := NewMerkleTree()
+	content := []byte("benchmark")
+	for i := 0; i < b.N; i++ {
+		mt.AddFile(content)
+	}
+}
+
+func TestUpdateLeaf(t *testing.T) {
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}, {'d'}, {'e'}}
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddLeaves(leaves)
+	oldRoot, _ := mt.RootDigest()
+	oldProof, _ := mt.GenerateProof(4)
+
+	if err := mt.UpdateLeaf(4, []byte{'x'}); err != nil {
+		t.Fatalf("Failed to update leaf: %v", err)
+	}
+	newRoot, _ := mt.RootDigest()
+
+	expected := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	expected.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}, {'d'}, {'x'}})
+	if !bytes.Equal(newRoot.Hash, expected.Root.Hash) {
+		t.Errorf("Root after update should match a tree built with the new content")
+	}
+	if bytes.Equal(oldRoot.Hash, newRoot.Hash) {
+		t.Errorf("Root should change after an update")
+	}
+	if err := VerifyProof(leaves[4], oldProof, newRoot); err == nil {
+		t.Errorf("Old proof should not verify against the new root")
+	}
+
+	for i, leaf := range [][]byte{{'a'}, {'b'}, {'c'}, {'d'}, {'x'}} {
+		proof, err := mt.GenerateProof(i)
+		if err != nil {
+			t.Fatalf("Failed to generate proof: %v", err)
+		}
+		if err := VerifyProof(leaf, proof, newRoot); err != nil {
+			t.Errorf("Proof for leaf %d did not verify after update: %v", i, err)
+		}
+	}
+
+	if err := mt.UpdateLeaf(5, []byte{'y'}); err == nil {
+		t.Errorf("Updating an out of range leaf should fail")
+	}
+}
+
+func TestRemoveLeaf(t *testing.T) {
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}, {'d'}}
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	mt.AddLeaves(leaves)
+	oldRoot, _ := mt.RootDigest()
+	oldProof, _ := mt.GenerateProof(0)
+
+	if err := mt.RemoveLeaf(1); err != nil {
+		t.Fatalf("Failed to remove leaf: %v", err)
+	}
+	newRoot, _ := mt.RootDigest()
+
+	if bytes.Equal(oldRoot.Hash, newRoot.Hash) {
+		t.Errorf("Root should change after a removal")
+	}
+	if !mt.IsRemoved(1) || mt.IsRemoved(0) {
+		t.Errorf("Only leaf 1 should be marked as removed")
+	}
+	if len(mt.Leaves) != 4 {
+		t.Errorf("Removal should keep leaf positions, got %d leaves", len(mt.Leaves))
+	}
+	if _, err := mt.GenerateProof(1); err == nil {
+		t.Errorf("Removed leaf should not have a proof")
+	}
+	if mt.GetIndexFromContent(leaves[1]) != -1 {
+		t.Errorf("Removed leaf should not be found by content")
+	}
+	if err := VerifyProof(leaves[0], oldProof, newRoot); err == nil {
+		t.Errorf("Old proof should not verify against the new root")
+	}
+	if err := mt.RemoveLeaf(1); err == nil {
+		t.Errorf("Removing a leaf twice should fail")
+	}
+
+	for _, i := range []int{0, 2, 3} {
+		proof, err := mt.GenerateProof(i)
+		if err != nil {
+			t.Fatalf("Failed to generate proof: %v", err)
+		}
+		if err := VerifyProof(leaves[i], proof, newRoot); err != nil {
+			t.Errorf("Proof for leaf %d did not verify after removal: %v", i, err)
+		}
+	}
+
+	if err := mt.AddFile([]byte{'e'}); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if !mt.IsRemoved(1) {
+		t.Errorf("Tombstone should survive later appends")
+	}
+}
+
+func TestGetIndicesFromContent(t *testing.T) {
+	mt := NewMerkleTree()
+	mt.AddLeaves([][]byte{{'a'}, {'b'}, {'a'}})
+	mt.AddFile([]byte{'a'})
+	mt.AddFile([]byte{'c'})
+
+	assertIndices := func(content byte, want ...int) {
+		t.Helper()
+		got := mt.GetIndicesFromContent([]byte{content})
+		if len(got) != len(want) {
+			t.Fatalf("Expected indices %v for %q, got %v", want, content, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Expected indices %v for %q, got %v", want, content, got)
+			}
+		}
+	}
+
+	assertIndices('a', 0, 2, 3)
+	assertIndices('b', 1)
+	assertIndices('c', 4)
+	assertIndices('z')
+	if mt.GetIndexFromContent([]byte{'a'}) != 0 {
+		t.Errorf("GetIndexFromContent should return the first match")
+	}
+
+	mt.UpdateLeaf(2, []byte{'b'})
+	assertIndices('a', 0, 3)
+	assertIndices('b', 1, 2)
+
+	mt.RemoveLeaf(0)
+	assertIndices('a', 3)
+
+	mt.UpdateLeaf(4, []byte{'a'})
+	assertIndices('a', 3, 4)
+	assertIndices('c')
+
+	// A full rebuild must keep the index and skip tombstones.
+	mt.AddLeaves([][]byte{{'b'}})
+	assertIndices('a', 3, 4)
+	assertIndices('b', 1, 2, 5)
+}
+
+func BenchmarkGetIndexFromContent(b *testing.B) {
+	mt := NewMerkleTree()
+	for i := 0; i < 100000; i++ {
+		mt.AddFile([]byte(fmt.Sprint(i)))
+	}
+	content := []byte(fmt.Sprint(99999))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mt.GetIndexFromContent(content)
+	}
+}
+
+func TestParallelBuildMatchesSerial(t *testing.T) {
+	defer func(threshold int) { parallelThreshold = threshold }(parallelThreshold)
+	parallelThreshold = 4
+
+	for _, size := range []int{1, 2, 3, 4, 5, 7, 8, 9, 63, 64, 65, 1000, 1001} {
+		leaves := make([][]byte, size)
+		for i := range leaves {
+			leaves[i] = []byte(fmt.Sprint(i))
+		}
+
+		serial := NewMerkleTree(WithWorkers(1))
+		serial.AddLeaves(leaves)
+		for _, workers := range []int{2, 3, 8} {
+			parallel := NewMerkleTree(WithWorkers(workers))
+			parallel.AddLeaves(leaves)
+			if !bytes.Equal(parallel.Root.Hash, serial.Root.Hash) {
+				t.Fatalf("Root with %d workers differs for %d leaves", workers, size)
+			}
+
+			root, _ := parallel.RootDigest()
+			for _, i := range []int{0, size / 2, size - 1} {
+				proof, err := parallel.GenerateProof(i)
+				if err != nil {
+					t.Fatalf("Failed to generate proof: %v", err)
+				}
+				if err := VerifyProof(leaves[i], proof, root); err != nil {
+					t.Errorf("Proof for leaf %d of %d did not verify: %v", i, size, err)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkBuild(b *testing.B, workers int) {
+	leaves := make([][]byte, 1<<18)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprint(i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mt := NewMerkleTree(WithWorkers(workers))
+		mt.AddLeaves(leaves)
+	}
+}
+
+func BenchmarkBuildSerial(b *testing.B) {
+	benchmarkBuild(b, 1)
+}
+
+func BenchmarkBuildParallel(b *testing.B) {
+	benchmarkBuild(b, 0)
+}
+
+// sortedPairRoot computes a root the way the first version of the package
+// did: unprefixed SHA-256, each pair sorted before hashing, and the last node
+// of an odd level paired with itself.
+func sortedPairRoot(leaves [][]byte) []byte {
+	var nodes [][]byte
+	for _, leaf := range leaves {
+		hash := sha256.Sum256(leaf)
+		nodes = append(nodes, hash[:])
+	}
+	for len(nodes) > 1 {
+		var next [][]byte
+		for i := 0; i < len(nodes); i += 2 {
+			first, second := nodes[i], nodes[i]
+			if i+1 < len(nodes) {
+				second = nodes[i+1]
+			}
+			if bytes.Compare(first, second) > 0 {
+				first, second = second, first
+			}
+			hash := sha256.Sum256(append(append([]byte(nil), first...), second...))
+			next = append(next, hash[:])
+		}
+		nodes = next
+	}
+	return nodes[0]
+}
+
+func TestHashModeSortedReproducesFirstRoots(t *testing.T) {
+	opts := []Option{WithHashMode(HashModeSorted), WithShape(ShapeDuplicate)}
+	var leaves [][]byte
+	for size := 1; size <= 9; size++ {
+		leaves = append(leaves, []byte(fmt.Sprintf("file %d", size)))
+		mt := NewMerkleTree(opts...)
+		mt.AddLeaves(leaves)
+		root, _ := mt.RootDigest()
+		if !bytes.Equal(root.Hash, sortedPairRoot(leaves)) {
+			t.Fatalf("Root of %d leaves differs from the sorted-pair root", size)
+		}
+		compact := NewCompactTree(opts...)
+		compact.AddLeaves(leaves)
+		if other, _ := compact.RootDigest(); !bytes.Equal(other.Hash, root.Hash) {
+			t.Errorf("Compact root of %d leaves differs", size)
+		}
+		for i, leaf := range leaves {
+			proof, _ := mt.GenerateProof(i)
+			if err := VerifyProof(leaf, proof, root); err != nil {
+				t.Errorf("Proof of leaf %d of %d did not verify: %v", i, size, err)
+			}
+		}
+	}
+
+	// Name indexes keep hashing children in position order.
+	names := NewSparseMerkleTree(opts...)
+	names.Set("present", []byte("value"))
+	if mode := names.RootDigest().Mode; mode != HashModeLegacy {
+		t.Errorf("Sparse tree uses mode %v", mode)
+	}
+	if err := VerifySparseProof("absent", nil, names.GenerateProof("absent"), names.RootDigest()); err != nil {
+		t.Errorf("Absence proof did not verify: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package merkle
+
+import (
+	"errors"
+	"sync"
+)
+
+// MountainRange is a Merkle Mountain Range: an append-only list of perfect
+// binary trees, the peaks, of strictly decreasing size. Appending a leaf only
+// merges peaks of equal size and never changes an existing node, so it costs
+// O(log n) hashes. The root bags the peaks from right to left, which makes it
+// the root of a ShapePromote tree of the same leaves, and a root determines its
+// tree size. It is safe for concurrent use.
+type MountainRange struct {
+	hasher hasher
+	// nodes[h] holds the root of every perfect subtree of height h, in
+	// order. The last one is a peak when there is an odd number of them.
+	nodes [][][]byte
+	// index maps a leaf hash to the positions of every leaf with that hash.
+	index map[string][]int
+	// mu guards nodes and index.
+	mu sync.RWMutex
+}
+
+// NewMountainRange creates an empty MountainRange. WithShape is ignored, as
+// the shape is always ShapePromote.
+func NewMountainRange(opts ...Option) *MountainRange {
+	c := newConfig(opts)
+	return &MountainRange{
+		hasher: c.hasher,
+		nodes:  [][][]byte{nil},
+		index:  make(map[string][]int),
+	}
+}
+
+// Algorithm returns the hash algorithm the range was built with.
+func (m *MountainRange) Algorithm() Algorithm {
+	return m.hasher.alg
+}
+
+// Mode returns the hashing mode the range was built with.
+func (m *MountainRange) Mode() HashMode {
+	return m.hasher.mode
+}
+
+// Shape returns ShapePromote, the shape of every range.
+func (m *MountainRange) Shape() Shape {
+	return ShapePromote
+}
+
+// Size returns the number of leaves in the range.
+func (m *MountainRange) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.nodes[0])
+}
+
+// AddFile appends a file as a new leaf, merging the peaks it completes.
+func (m *MountainRange) AddFile(fileContent []byte) error {
+	hash := m.hasher.leaf(fileContent)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.index[string(hash)] = append(m.index[string(hash)], len(m.nodes[0]))
+	m.nodes[0] = append(m.nodes[0], hash)
+	for h := 0; len(m.nodes[h])%2 == 0; h++ {
+		if h+1 == len(m.nodes) {
+			m.nodes = append(m.nodes, nil)
+		}
+		level := m.nodes[h]
+		parent := m.hasher.children(level[len(level)-2], level[len(level)-1])
+		m.nodes[h+1] = append(m.nodes[h+1], parent)
+	}
+	return nil
+}
+
+// AddEntry appends a named file as a new leaf whose data is its encoded Entry.
+func (m *MountainRange) AddEntry(name string, content []byte) error {
+	return m.AddFile(m.hasher.entry(name, content).Encode())
+}
+
+// RootDigest returns the peaks bagged from right to left.
+func (m *MountainRange) RootDigest() (Digest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rootDigest()
+}
+
+func (m *MountainRange) rootDigest() (Digest, error) {
+	if len(m.nodes[0]) == 0 {
+		return Digest{}, errors.New("mountain range is empty")
+	}
+	return Digest{
+		Algorithm: m.hasher.alg,
+		Mode:      m.hasher.mode,
+		Shape:     ShapePromote,
+		Hash:      m.bag(m.peakHeights()),
+	}, nil
+}
+
+// peakHeights returns the height of every peak, from the leftmost, highest
+// one to the rightmost.
+func (m *MountainRange) peakHeights() []int {
+	var heights []int
+	for h := len(m.nodes) - 1; h >= 0; h-- {
+		if len(m.nodes[h])%2 == 1 {
+			heights = append(heights, h)
+		}
+	}
+	return heights
+}
+
+// peak returns the hash of the peak of height h.
+func (m *MountainRange) peak(h int) []byte {
+	return m.nodes[h][len(m.nodes[h])-1]
+}
+
+// bag hashes the given peaks together from right to left.
+func (m *MountainRange) bag(heights []int) []byte {
+	acc := m.peak(heights[len(heights)-1])
+	for i := len(heights) - 2; i >= 0; i-- {
+		acc = m.hasher.children(m.peak(heights[i]), acc)
+	}
+	return acc
+}
+
+// GenerateProof returns an inclusion proof for the leaf at leafIndex: the path
+// to its peak, the bag of the peaks to its right, then the peaks to its left
+// from the nearest one.
+func (m *MountainRange) GenerateProof(leafIndex int) (*Proof, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.generateProof(leafIndex)
+}
+
+// GenerateProofWithRoot returns an inclusion proof for the leaf at leafIndex
+// together with the root it verifies against.
+func (m *MountainRange) GenerateProofWithRoot(leafIndex int) (*Proof, Digest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	proof, err := m.generateProof(leafIndex)
+	if err != nil {
+		return nil, Digest{}, err
+	}
+	root, err := m.rootDigest()
+	if err != nil {
+		return nil, Digest{}, err
+	}
+	return proof, root, nil
+}
+
+func (m *MountainRange) generateProof(leafIndex int) (*Proof, error) {
+	if leafIndex < 0 || leafIndex >= len(m.nodes[0]) {
+		return nil, errors.New("invalid leaf index")
+	}
+
+	proof := &Proof{
+		Algorithm: m.hasher.alg,
+		Mode:      m.hasher.mode,
+		Shape:     ShapePromote,
+		LeafIndex: leafIndex,
+		TreeSize:  len(m.nodes[0]),
+	}
+
+	// Climb while the node has a parent; the node reached is a peak.
+	h, i := 0, leafIndex
+	for ; h+1 < len(m.nodes) && i/2 < len(m.nodes[h+1]); h++ {
+		proof.Siblings = append(proof.Siblings, m.nodes[h][i^1])
+		proof.Left = append(proof.Left, i%2 == 1)
+		i /= 2
+	}
+
+	heights := m.peakHeights()
+	p := 0
+	for heights[p] != h {
+		p++
+	}
+	if p+1 < len(heights) {
+		proof.Siblings = append(proof.Siblings, m.bag(heights[p+1:]))
+		proof.Left = append(proof.Left, false)
+	}
+	for q := p - 1; q >= 0; q-- {
+		proof.Siblings = append(proof.Siblings, m.peak(heights[q]))
+		proof.Left = append(proof.Left, true)
+	}
+	return proof, nil
+}
+
+// GetIndicesFromContent returns, in ascending order, the index of every leaf
+// with the given content.
+func (m *MountainRange) GetIndicesFromContent(content []byte) []int {
+	hash := m.hasher.leaf(content)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]int(nil), m.index[string(hash)]...)
+}
+
+// GetIndicesFromEntry returns, in ascending order, the index of every leaf
+// holding the named file with the given content.
+func (m *MountainRange) GetIndicesFromEntry(name string, content []byte) []int {
+	return m.GetIndicesFromContent(m.hasher.entry(name, content).Encode())
+}
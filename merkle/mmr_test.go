@@ -0,0 +1,113 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// rfc6962Root is the MTH function of RFC 6962, section 2.1.
+func rfc6962Root(h hasher, leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return h.leaf(leaves[0])
+	}
+	k := 1
+	for k<<1 < len(leaves) {
+		k <<= 1
+	}
+	return h.children(rfc6962Root(h, leaves[:k]), rfc6962Root(h, leaves[k:]))
+}
+
+func TestMountainRange(t *testing.T) {
+	opts := []Option{WithHashMode(HashModeRFC6962)}
+	m := NewMountainRange(opts...)
+	compact := NewCompactTree(append(opts, WithShape(ShapePromote))...)
+	var leaves [][]byte
+	for size := 1; size <= 40; size++ {
+		leaf := []byte(fmt.Sprint(size))
+		leaves = append(leaves, leaf)
+		m.AddFile(leaf)
+		compact.AddFile(leaf)
+
+		root, err := m.RootDigest()
+		if err != nil {
+			t.Fatalf("Failed to compute root: %v", err)
+		}
+		if !bytes.Equal(root.Hash, rfc6962Root(m.hasher, leaves)) {
+			t.Fatalf("Root of %d leaves is not the RFC 6962 root", size)
+		}
+		compactRoot, _ := compact.RootDigest()
+		if root.Shape != ShapePromote || compactRoot.Shape != ShapePromote || !bytes.Equal(root.Hash, compactRoot.Hash) {
+			t.Fatalf("Root of %d leaves differs from the compact tree's", size)
+		}
+
+		for i, leaf := range leaves {
+			proof, err := m.GenerateProof(i)
+			if err != nil {
+				t.Fatalf("Failed to generate proof: %v", err)
+			}
+			if err := VerifyProof(leaf, proof, root); err != nil {
+				t.Errorf("Proof for leaf %d of %d did not verify: %v", i, size, err)
+			}
+		}
+	}
+}
+
+func TestMountainRangeRootDeterminesSize(t *testing.T) {
+	three, four := NewMountainRange(), NewMountainRange()
+	for _, leaf := range []string{"a", "b", "c"} {
+		three.AddFile([]byte(leaf))
+		four.AddFile([]byte(leaf))
+	}
+	four.AddFile([]byte("c"))
+
+	threeRoot, _ := three.RootDigest()
+	fourRoot, _ := four.RootDigest()
+	if bytes.Equal(threeRoot.Hash, fourRoot.Hash) {
+		t.Error("[a, b, c] and [a, b, c, c] should have different roots")
+	}
+
+	// The format 1 shape cannot tell them apart.
+	threeTree, fourTree := NewMerkleTree(WithShape(ShapeDuplicate)), NewMerkleTree(WithShape(ShapeDuplicate))
+	threeTree.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+	fourTree.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}, {'c'}})
+	if !bytes.Equal(threeTree.Root.Hash, fourTree.Root.Hash) {
+		t.Error("MerkleTree roots were expected to collide")
+	}
+}
+
+func TestMountainRangeProofRejectsWrongShape(t *testing.T) {
+	m := NewMountainRange()
+	m.AddEntry("a", []byte("a"))
+	m.AddEntry("b", []byte("b"))
+	m.AddEntry("c", []byte("c"))
+
+	proof, root, err := m.GenerateProofWithRoot(2)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	if err := VerifyEntryProof("c", []byte("c"), proof, root); err != nil {
+		t.Fatalf("Proof did not verify: %v", err)
+	}
+
+	root.Shape = ShapeDuplicate
+	if err := VerifyEntryProof("c", []byte("c"), proof, root); err == nil {
+		t.Error("Proof should not verify against a root of another shape")
+	}
+	proof.Shape, root.Shape = ShapeDuplicate, ShapeDuplicate
+	if err := VerifyEntryProof("c", []byte("c"), proof, root); err == nil {
+		t.Error("Promoted path should not verify as a duplicate-shape proof")
+	}
+
+	if indices := m.GetIndicesFromEntry("b", []byte("b")); len(indices) != 1 || indices[0] != 1 {
+		t.Errorf("Expected entry b at index 1, got %v", indices)
+	}
+}
+
+func BenchmarkMountainRangeAddFile(b *testing.B) {
+	m := NewMountainRange()
+	content := []byte("benchmark")
+	for i := 0; i < b.N; i++ {
+		m.AddFile(content)
+	}
+}
@@ -0,0 +1,195 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MultiProof proves several leaves of a MerkleTree at once. Interior nodes
+// shared by the paths of several leaves, or computable from the leaves
+// themselves, are sent only once or not at all.
+type MultiProof struct {
+	Algorithm Algorithm
+	Mode      HashMode
+	Shape     Shape
+	// Indices are the positions of the proven leaves, in the order the
+	// leaves are passed to VerifyMultiProof.
+	Indices  []int
+	TreeSize int
+	// Hashes holds the sibling hashes the verifier cannot compute, level by
+	// level from the leaves up and by increasing position within a level.
+	Hashes [][]byte
+}
+
+// GenerateMultiProof returns a proof for the leaves at the given indices.
+func (mt *MerkleTree) GenerateMultiProof(indices []int) (*MultiProof, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	return mt.generateMultiProof(indices)
+}
+
+// GenerateMultiProofWithRoot returns a proof for the leaves at the given
+// indices together with the root it verifies against, read at once as in
+// GenerateProofWithRoot.
+func (mt *MerkleTree) GenerateMultiProofWithRoot(indices []int) (*MultiProof, Digest, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	proof, err := mt.generateMultiProof(indices)
+	if err != nil {
+		return nil, Digest{}, err
+	}
+	root, err := mt.rootDigest()
+	if err != nil {
+		return nil, Digest{}, err
+	}
+	return proof, root, nil
+}
+
+func (mt *MerkleTree) generateMultiProof(indices []int) (*MultiProof, error) {
+	if len(indices) == 0 {
+		return nil, errors.New("no leaf indices")
+	}
+	known, err := sortedIndices(indices, len(mt.Leaves))
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range known {
+		if mt.isRemoved(i) {
+			return nil, fmt.Errorf("leaf %d has been removed", i)
+		}
+	}
+
+	proof := &MultiProof{
+		Algorithm: mt.hasher.alg,
+		Mode:      mt.hasher.mode,
+		Shape:     mt.shape,
+		Indices:   append([]int(nil), indices...),
+		TreeSize:  len(mt.Leaves),
+	}
+	for l := 0; len(mt.levels[l]) > 1; l++ {
+		nodes := mt.levels[l]
+		var parents []int
+		for j := 0; j < len(known); j++ {
+			i := known[j]
+			switch {
+			case i%2 == 0 && j+1 < len(known) && known[j+1] == i+1:
+				// Both children are known.
+				j++
+			case i%2 == 1:
+				proof.Hashes = append(proof.Hashes, nodes[i-1].Hash)
+			case i+1 < len(nodes):
+				proof.Hashes = append(proof.Hashes, nodes[i+1].Hash)
+			}
+			parents = append(parents, i/2)
+		}
+		known = parents
+	}
+	return proof, nil
+}
+
+// VerifyMultiProof checks that leaves[i] is stored at proof.Indices[i] for
+// every i in the tree whose root is root.
+func VerifyMultiProof(leaves [][]byte, proof *MultiProof, root Digest) error {
+	if proof == nil {
+		return errors.New("missing proof")
+	}
+	if proof.Algorithm != root.Algorithm {
+		return fmt.Errorf("proof uses hash algorithm %q, root uses %q", proof.Algorithm, root.Algorithm)
+	}
+	if proof.Mode != root.Mode {
+		return fmt.Errorf("proof uses hash mode %v, root uses %v", proof.Mode, root.Mode)
+	}
+	if proof.Shape != root.Shape {
+		return fmt.Errorf("proof is for tree shape %v, root is for %v", proof.Shape, root.Shape)
+	}
+	if !proof.Shape.valid() {
+		return fmt.Errorf("unknown tree shape %v", proof.Shape)
+	}
+	h, err := lookupHasher(proof.Algorithm, proof.Mode)
+	if err != nil {
+		return err
+	}
+	if len(leaves) == 0 || len(leaves) != len(proof.Indices) {
+		return fmt.Errorf("got %d leaves for %d indices", len(leaves), len(proof.Indices))
+	}
+	if _, err := sortedIndices(proof.Indices, proof.TreeSize); err != nil {
+		return err
+	}
+
+	type node struct {
+		index int
+		hash  []byte
+	}
+	known := make([]node, len(leaves))
+	for i, leaf := range leaves {
+		known[i] = node{index: proof.Indices[i], hash: h.leaf(leaf)}
+	}
+	sort.Slice(known, func(a, b int) bool { return known[a].index < known[b].index })
+
+	hashes := proof.Hashes
+	next := func() ([]byte, error) {
+		if len(hashes) == 0 {
+			return nil, errors.New("proof is too short")
+		}
+		hash := hashes[0]
+		hashes = hashes[1:]
+		return hash, nil
+	}
+
+	for size := proof.TreeSize; size > 1; size = (size + 1) / 2 {
+		var parents []node
+		for j := 0; j < len(known); j++ {
+			n := known[j]
+			var parent []byte
+			switch {
+			case n.index%2 == 0 && j+1 < len(known) && known[j+1].index == n.index+1:
+				parent = h.children(n.hash, known[j+1].hash)
+				j++
+			case n.index%2 == 1:
+				sibling, err := next()
+				if err != nil {
+					return err
+				}
+				parent = h.children(sibling, n.hash)
+			case n.index+1 < size:
+				sibling, err := next()
+				if err != nil {
+					return err
+				}
+				parent = h.children(n.hash, sibling)
+			default:
+				// The last node of an odd level is promoted or paired
+				// with itself.
+				parent = h.unpaired(n.hash, proof.Shape)
+			}
+			parents = append(parents, node{index: n.index / 2, hash: parent})
+		}
+		known = parents
+	}
+
+	if len(hashes) != 0 {
+		return fmt.Errorf("proof has %d unused hashes", len(hashes))
+	}
+	if !bytes.Equal(known[0].hash, root.Hash) {
+		return errors.New("computed root does not match")
+	}
+	return nil
+}
+
+// sortedIndices returns indices in increasing order, checking that they are
+// distinct positions in a tree of size leaves.
+func sortedIndices(indices []int, size int) ([]int, error) {
+	sorted := append([]int(nil), indices...)
+	sort.Ints(sorted)
+	for j, i := range sorted {
+		if i < 0 || i >= size {
+			return nil, fmt.Errorf("leaf index %d out of range for tree size %d", i, size)
+		}
+		if j > 0 && sorted[j-1] == i {
+			return nil, fmt.Errorf("duplicate leaf index %d", i)
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,100 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMultiProof(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		var leaves [][]byte
+		for i := 0; i < size; i++ {
+			leaves = append(leaves, []byte{byte(i)})
+		}
+		mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+		mt.AddLeaves(leaves)
+		root, _ := mt.RootDigest()
+
+		// Every subset of up to the first 9 leaves, in reverse order.
+		limit := size
+		if limit > 9 {
+			limit = 9
+		}
+		for mask := 1; mask < 1<<limit; mask++ {
+			var indices []int
+			var proven [][]byte
+			for i := limit - 1; i >= 0; i-- {
+				if mask&(1<<i) != 0 {
+					indices = append(indices, i)
+					proven = append(proven, leaves[i])
+				}
+			}
+
+			proof, err := mt.GenerateMultiProof(indices)
+			if err != nil {
+				t.Fatalf("Failed to generate proof: %v", err)
+			}
+			if err := VerifyMultiProof(proven, proof, root); err != nil {
+				t.Errorf("Proof of %v in %d leaves did not verify: %v", indices, size, err)
+			}
+
+			if len(indices) > 1 {
+				swapped := append([][]byte{proven[1], proven[0]}, proven[2:]...)
+				if err := VerifyMultiProof(swapped, proof, root); err == nil {
+					t.Errorf("Proof of %v should not verify with swapped leaves", indices)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiProofIsSmallerThanSingleProofs(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	for i := 0; i < 64; i++ {
+		mt.AddFile([]byte{byte(i)})
+	}
+	indices := []int{0, 1, 2, 3, 8, 9, 10, 11}
+
+	proof, err := mt.GenerateMultiProof(indices)
+	if err != nil {
+		t.Fatalf("Failed to generate proof: %v", err)
+	}
+	single := 0
+	for _, i := range indices {
+		p, _ := mt.GenerateProof(i)
+		single += len(p.Siblings)
+	}
+	// The two groups of four only need their uncle at level 2 and the
+	// siblings of their common ancestor above it.
+	if len(proof.Hashes) != 4 || len(proof.Hashes) >= single {
+		t.Errorf("Expected 4 hashes, got %d (single proofs need %d)", len(proof.Hashes), single)
+	}
+}
+
+func TestMultiProofRejectsInvalidInput(t *testing.T) {
+	mt := NewMerkleTree()
+	mt.AddLeaves([][]byte{{'a'}, {'b'}, {'c'}})
+	root, _ := mt.RootDigest()
+
+	for _, indices := range [][]int{nil, {1, 1}, {3}, {-1}} {
+		if _, err := mt.GenerateMultiProof(indices); err == nil {
+			t.Errorf("Proof of %v should fail", indices)
+		}
+	}
+
+	proof, _ := mt.GenerateMultiProof([]int{0, 2})
+	if err := VerifyMultiProof([][]byte{{'a'}}, proof, root); err == nil {
+		t.Error("Proof should not verify with missing leaves")
+	}
+	tampered := *proof
+	tampered.Hashes = append([][]byte(nil), proof.Hashes...)
+	tampered.Hashes[0] = bytes.Repeat([]byte{1}, 32)
+	if err := VerifyMultiProof([][]byte{{'a'}, {'c'}}, &tampered, root); err == nil {
+		t.Error("Tampered proof should not verify")
+	}
+	moved := *proof
+	moved.Indices = []int{1, 2}
+	if err := VerifyMultiProof([][]byte{{'a'}, {'c'}}, &moved, root); err == nil {
+		t.Error("Proof should not verify at other positions")
+	}
+}
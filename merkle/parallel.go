@@ -0,0 +1,50 @@
+package merkle
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelThreshold is the number of items below which a level or a batch of
+// leaves is hashed on the calling goroutine: smaller jobs do not pay for
+// starting workers.
+var parallelThreshold = 2048
+
+// WithWorkers sets how many goroutines hash large batches of leaves and the
+// levels above them. Trees use runtime.GOMAXPROCS workers unless told
+// otherwise; one worker builds serially. The resulting tree does not depend
+// on the number of workers.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// parallelFor calls fn over consecutive ranges [lo, hi) that cover [0, n),
+// on up to workers goroutines. Ranges start on even positions, so that a pair
+// of siblings is never split between two workers.
+func parallelFor(n, workers int, fn func(lo, hi int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers == 1 || n < parallelThreshold {
+		fn(0, n)
+		return
+	}
+
+	size := (n + workers - 1) / workers
+	size += size % 2
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
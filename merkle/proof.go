@@ -0,0 +1,83 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Proof is an inclusion proof for a single leaf of a MerkleTree or a
+// MountainRange.
+type Proof struct {
+	// Algorithm is the hash algorithm of the tree the proof was built from.
+	Algorithm Algorithm
+	// Mode is the hashing mode of the tree the proof was built from.
+	Mode HashMode
+	// Shape is the shape of the tree the proof was built from.
+	Shape Shape
+	// LeafIndex is the position of the proven leaf.
+	LeafIndex int
+	// TreeSize is the number of leaves in the tree the proof was built from.
+	TreeSize int
+	// Siblings holds the sibling hashes from the leaf level up to the root.
+	Siblings [][]byte
+	// Left reports, for each sibling, whether it is the left input of the
+	// parent hash.
+	Left []bool
+}
+
+// VerifyProof checks that leaf is stored at proof.LeafIndex in the tree whose
+// root is root. The proof and root must agree on algorithm and mode, and the
+// sibling directions must match the path implied by the leaf index and tree
+// size, so a proof cannot be replayed for another position.
+func VerifyProof(leaf []byte, proof *Proof, root Digest) error {
+	if proof == nil {
+		return errors.New("missing proof")
+	}
+	if proof.Algorithm != root.Algorithm {
+		return fmt.Errorf("proof uses hash algorithm %q, root uses %q", proof.Algorithm, root.Algorithm)
+	}
+	if proof.Mode != root.Mode {
+		return fmt.Errorf("proof uses hash mode %v, root uses %v", proof.Mode, root.Mode)
+	}
+	if proof.Shape != root.Shape {
+		return fmt.Errorf("proof is for tree shape %v, root is for %v", proof.Shape, root.Shape)
+	}
+	if !proof.Shape.valid() {
+		return fmt.Errorf("unknown tree shape %v", proof.Shape)
+	}
+	h, err := lookupHasher(proof.Algorithm, proof.Mode)
+	if err != nil {
+		return err
+	}
+	if proof.LeafIndex < 0 || proof.LeafIndex >= proof.TreeSize {
+		return fmt.Errorf("leaf index %d out of range for tree size %d", proof.LeafIndex, proof.TreeSize)
+	}
+	if len(proof.Left) != len(proof.Siblings) {
+		return errors.New("proof has mismatched siblings and directions")
+	}
+
+	path := pathDirections(proof.LeafIndex, proof.TreeSize, proof.Shape)
+	if len(path) != len(proof.Siblings) {
+		return fmt.Errorf("proof has %d siblings, expected %d", len(proof.Siblings), len(path))
+	}
+	for i, left := range path {
+		if proof.Left[i] != left {
+			return fmt.Errorf("sibling %d is on the wrong side for leaf %d", i, proof.LeafIndex)
+		}
+	}
+
+	current := h.leaf(leaf)
+	for i, sibling := range proof.Siblings {
+		if proof.Left[i] {
+			current = h.children(sibling, current)
+		} else {
+			current = h.children(current, sibling)
+		}
+	}
+
+	if !bytes.Equal(current, root.Hash) {
+		return errors.New("computed root does not match")
+	}
+	return nil
+}
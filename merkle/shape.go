@@ -0,0 +1,73 @@
+package merkle
+
+import "fmt"
+
+// Shape selects how a tree pairs up nodes when a level has an odd number of
+// them.
+//
+// The shape is the tree format version: format 1 trees have ShapeDuplicate and
+// format 2 trees, the default since, have ShapePromote. Deployments with
+// format 1 roots keep them with WithShape(ShapeDuplicate), and move to format 2
+// with MerkleTree.Migrate. Chunk trees keep ShapeDuplicate in both formats, so
+// entries and leaf hashes do not change between them.
+type Shape uint8
+
+const (
+	// ShapeDuplicate pairs the last node of an odd level with itself. Trees
+	// of different sizes can share a root, as [a, b, c] and [a, b, c, c] do,
+	// so a proof can claim a phantom copy of the last leaf.
+	ShapeDuplicate Shape = iota
+	// ShapePromote moves the last node of an odd level up unchanged. This is
+	// the shape of RFC 6962 trees, and of a MountainRange whose peaks are
+	// bagged from right to left. A root determines its tree size.
+	ShapePromote
+)
+
+// DefaultShape is the shape of trees built without WithShape.
+const DefaultShape = ShapePromote
+
+func (s Shape) String() string {
+	switch s {
+	case ShapeDuplicate:
+		return "duplicate"
+	case ShapePromote:
+		return "promote"
+	default:
+		return fmt.Sprintf("Shape(%d)", uint8(s))
+	}
+}
+
+func (s Shape) valid() bool {
+	return s == ShapeDuplicate || s == ShapePromote
+}
+
+// WithShape selects the shape of a MerkleTree or CompactTree. Trees use
+// DefaultShape unless told otherwise; MountainRange always uses ShapePromote.
+func WithShape(shape Shape) Option {
+	return func(c *config) {
+		c.shape = shape
+	}
+}
+
+// unpaired returns the parent of the last node of an odd level.
+func (h hasher) unpaired(node []byte, shape Shape) []byte {
+	if shape == ShapePromote {
+		return node
+	}
+	return h.children(node, node)
+}
+
+// pathDirections returns, for each sibling on the path from the given leaf to
+// the root, whether it sits on the left. A promoted node has no sibling at
+// its level.
+func pathDirections(index, size int, shape Shape) []bool {
+	var path []bool
+	for size > 1 {
+		if shape == ShapeDuplicate || index%2 == 1 || index+1 < size {
+			path = append(path, index%2 == 1)
+		}
+		index /= 2
+		size = (size + 1) / 2
+	}
+	return path
+}
@@ -0,0 +1,193 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// duplicateRoot is the root of a format 1 tree, built the way recalculateTree
+// did before shapes existed.
+func duplicateRoot(h hasher, leaves [][]byte) []byte {
+	var level [][]byte
+	for _, leaf := range leaves {
+		level = append(level, h.leaf(leaf))
+	}
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, h.children(level[i], right))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+func TestShapesAgainstReference(t *testing.T) {
+	var leaves [][]byte
+	for size := 1; size <= 33; size++ {
+		leaves = append(leaves, []byte(fmt.Sprint(size)))
+		for shape, reference := range map[Shape]func(hasher, [][]byte) []byte{
+			ShapeDuplicate: duplicateRoot,
+			ShapePromote:   rfc6962Root,
+		} {
+			batch := NewMerkleTree(WithShape(shape), WithHashMode(HashModeRFC6962))
+			batch.AddLeaves(leaves)
+			incremental := NewMerkleTree(WithShape(shape), WithHashMode(HashModeRFC6962))
+			for _, leaf := range leaves {
+				incremental.AddFile(leaf)
+			}
+
+			want := reference(batch.hasher, leaves)
+			if !bytes.Equal(batch.Root.Hash, want) || !bytes.Equal(incremental.Root.Hash, want) {
+				t.Fatalf("%v root of %d leaves differs from the reference", shape, size)
+			}
+			compact := NewCompactTree(WithShape(shape), WithHashMode(HashModeRFC6962))
+			compact.AddLeaves(leaves)
+			if root, _ := compact.RootDigest(); !bytes.Equal(root.Hash, want) || root.Shape != shape {
+				t.Fatalf("%v compact root of %d leaves differs from the reference", shape, size)
+			}
+		}
+	}
+}
+
+func TestShapesProveEveryLeaf(t *testing.T) {
+	for _, shape := range []Shape{ShapeDuplicate, ShapePromote} {
+		mt := NewMerkleTree(WithShape(shape))
+		var leaves [][]byte
+		for size := 1; size <= 20; size++ {
+			leaf := []byte{byte(size)}
+			leaves = append(leaves, leaf)
+			mt.AddFile(leaf)
+
+			root, _ := mt.RootDigest()
+			snapshot, _ := mt.Snapshot()
+			for i := range leaves {
+				proof, _ := mt.GenerateProof(i)
+				if err := VerifyProof(leaves[i], proof, root); err != nil {
+					t.Errorf("%v proof for leaf %d of %d did not verify: %v", shape, i, size, err)
+				}
+				proof, _ = snapshot.GenerateProof(i)
+				if err := VerifyProof(leaves[i], proof, snapshot.RootDigest()); err != nil {
+					t.Errorf("%v snapshot proof for leaf %d of %d did not verify: %v", shape, i, size, err)
+				}
+			}
+
+			indices := []int{0}
+			if size > 1 {
+				indices = append(indices, size-1)
+			}
+			if size > 2 {
+				indices = append(indices, size/2)
+			}
+			multi, err := mt.GenerateMultiProof(indices)
+			if err != nil {
+				t.Fatalf("Failed to generate multiproof: %v", err)
+			}
+			var proven [][]byte
+			for _, i := range indices {
+				proven = append(proven, leaves[i])
+			}
+			if err := VerifyMultiProof(proven, multi, root); err != nil {
+				t.Errorf("%v multiproof for %v of %d did not verify: %v", shape, indices, size, err)
+			}
+
+			for oldSize := 1; oldSize <= size; oldSize++ {
+				oldRoot, _ := mt.RootAt(oldSize)
+				proof, _ := mt.ConsistencyProof(oldSize, size)
+				if err := VerifyConsistency(proof, oldRoot, root); err != nil {
+					t.Errorf("%v consistency proof from %d to %d did not verify: %v", shape, oldSize, size, err)
+				}
+			}
+		}
+	}
+}
+
+func TestShapePromoteRejectsPhantomLeaf(t *testing.T) {
+	leaves := [][]byte{{'a'}, {'b'}, {'c'}}
+	phantom := append(leaves, []byte{'c'})
+	for _, shape := range []Shape{ShapeDuplicate, ShapePromote} {
+		mt := NewMerkleTree(WithShape(shape))
+		mt.AddLeaves(leaves)
+		root, _ := mt.RootDigest()
+
+		// Prove a fourth leaf, a copy of the third, against the root of
+		// three leaves.
+		forged := NewMerkleTree(WithShape(shape))
+		forged.AddLeaves(phantom)
+		proof, _ := forged.GenerateProof(3)
+		err := VerifyProof([]byte{'c'}, proof, root)
+		if shape == ShapeDuplicate && err != nil {
+			t.Errorf("Expected the duplicate shape to accept the phantom leaf: %v", err)
+		}
+		if shape == ShapePromote && err == nil {
+			t.Error("Phantom leaf should not verify in the promote shape")
+		}
+	}
+}
+
+func TestMigrateFormat1Tree(t *testing.T) {
+	legacy := NewMerkleTree(WithShape(ShapeDuplicate), WithHashMode(HashModeRFC6962))
+	var leaves [][]byte
+	for i := 0; i < 7; i++ {
+		leaves = append(leaves, testEntry(legacy, i))
+	}
+	legacy.AddLeaves(leaves)
+	legacy.RemoveLeaf(2)
+
+	// A format 1 state, written before shapes existed, still loads.
+	data, _ := legacy.MarshalBinary()
+	v1 := append([]byte{1, data[1]}, data[3:]...)
+	var loaded MerkleTree
+	if err := loaded.UnmarshalBinary(v1); err != nil {
+		t.Fatalf("Failed to load version 1 state: %v", err)
+	}
+	if loaded.Shape() != ShapeDuplicate || !bytes.Equal(loaded.Root.Hash, legacy.Root.Hash) {
+		t.Fatal("Version 1 state should load as the same duplicate-shape tree")
+	}
+
+	migrated, err := loaded.Migrate(ShapePromote)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	fresh := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	fresh.AddLeaves(leaves)
+	fresh.RemoveLeaf(2)
+	if migrated.Shape() != ShapePromote || !bytes.Equal(migrated.Root.Hash, fresh.Root.Hash) {
+		t.Error("Migrated tree should match a tree built in the new shape")
+	}
+	if bytes.Equal(migrated.Root.Hash, legacy.Root.Hash) {
+		t.Error("Shapes should give different roots for 7 leaves")
+	}
+	if !migrated.IsRemoved(2) || len(migrated.GetIndicesFromContent(leaves[4])) != 1 {
+		t.Error("Migration should keep removed leaves and the index")
+	}
+
+	// Old proofs do not carry over to the new root.
+	oldProof, _ := legacy.GenerateProof(6)
+	newRoot, _ := migrated.RootDigest()
+	if err := VerifyProof(leaves[6], oldProof, newRoot); err == nil {
+		t.Error("Format 1 proof should not verify against the migrated root")
+	}
+	newProof, _ := migrated.GenerateProof(6)
+	if err := VerifyProof(leaves[6], newProof, newRoot); err != nil {
+		t.Errorf("Proof from the migrated tree did not verify: %v", err)
+	}
+
+	// A version 1 state cannot claim another shape.
+	state := legacy.State()
+	state.Version, state.Shape = 1, ShapePromote
+	if _, err := NewMerkleTreeFromState(state); err == nil {
+		t.Error("Version 1 state with the promote shape should not load")
+	}
+}
+
+// testEntry returns the encoded entry of a test file numbered i.
+func testEntry(mt *MerkleTree, i int) []byte {
+	name := fmt.Sprint("file-", i)
+	return mt.hasher.entry(name, []byte(name)).Encode()
+}
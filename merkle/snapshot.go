@@ -0,0 +1,288 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultSnapshots is the number of past versions a MerkleTree keeps unless
+// told otherwise with WithSnapshots.
+const DefaultSnapshots = 1024
+
+// WithSnapshots keeps the last n versions of a MerkleTree, so proofs can still
+// be generated against roots that were published before later changes. The
+// current version is always available.
+func WithSnapshots(n int) Option {
+	return func(c *config) {
+		c.snapshots = n
+	}
+}
+
+// Snapshot is an immutable version of a MerkleTree. Later changes to the tree
+// do not affect it.
+type Snapshot struct {
+	hasher hasher
+	shape  Shape
+	// leaves holds the leaf hashes, shared with the tree and other versions.
+	leaves leafHashes
+	root   []byte
+
+	// levels is built from the leaves when a proof is requested, and kept
+	// while the snapshot is among the last few in cache to need it.
+	cache  *levelCache
+	mu     sync.Mutex
+	levels [][][]byte
+}
+
+// Size returns the number of leaves in the snapshot.
+func (s *Snapshot) Size() int {
+	return s.leaves.n
+}
+
+// RootDigest returns the root of the snapshot.
+func (s *Snapshot) RootDigest() Digest {
+	return Digest{
+		Algorithm: s.hasher.alg,
+		Mode:      s.hasher.mode,
+		Shape:     s.shape,
+		Hash:      s.root,
+	}
+}
+
+// GenerateProof returns an inclusion proof for the leaf at leafIndex against
+// the root of the snapshot.
+func (s *Snapshot) GenerateProof(leafIndex int) (*Proof, error) {
+	if leafIndex < 0 || leafIndex >= s.leaves.n {
+		return nil, errors.New("invalid leaf index")
+	}
+	if bytes.Equal(s.leaves.at(leafIndex), s.hasher.tombstone()) {
+		return nil, errors.New("leaf has been removed")
+	}
+	levels := s.getLevels()
+
+	proof := &Proof{
+		Algorithm: s.hasher.alg,
+		Mode:      s.hasher.mode,
+		Shape:     s.shape,
+		LeafIndex: leafIndex,
+		TreeSize:  s.leaves.n,
+	}
+	i := leafIndex
+	for _, nodes := range levels[:len(levels)-1] {
+		// As in MerkleTree, a duplicated node is the left input and a
+		// promoted one has no sibling.
+		sibling, left := i+1, false
+		if i%2 == 1 {
+			sibling, left = i-1, true
+		} else if sibling == len(nodes) {
+			sibling = i
+		}
+		if sibling != i || s.shape == ShapeDuplicate {
+			proof.Siblings = append(proof.Siblings, nodes[sibling])
+			proof.Left = append(proof.Left, left)
+		}
+		i /= 2
+	}
+	return proof, nil
+}
+
+// cachedLevels is the number of snapshots of a tree that keep their levels
+// between proofs. Every other snapshot only holds the leaf hashes it shares
+// with the tree.
+const cachedLevels = 4
+
+// levelCache tracks the snapshots of a tree whose levels are built, least
+// recently used first.
+type levelCache struct {
+	mu        sync.Mutex
+	snapshots []*Snapshot
+}
+
+// getLevels returns the levels of the snapshot, building them if they are not
+// cached.
+func (s *Snapshot) getLevels() [][][]byte {
+	s.mu.Lock()
+	levels := s.levels
+	s.mu.Unlock()
+	if levels == nil {
+		levels = s.buildLevels()
+		s.mu.Lock()
+		s.levels = levels
+		s.mu.Unlock()
+	}
+	if s.cache != nil {
+		s.cache.use(s)
+	}
+	return levels
+}
+
+// use marks s as the most recently used snapshot, dropping the levels of the
+// least recently used one beyond cachedLevels.
+func (c *levelCache) use(s *Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.snapshots {
+		if other == s {
+			c.snapshots = append(c.snapshots[:i], c.snapshots[i+1:]...)
+			break
+		}
+	}
+	c.snapshots = append(c.snapshots, s)
+	if len(c.snapshots) > cachedLevels {
+		evicted := c.snapshots[0]
+		c.snapshots = append(c.snapshots[:0], c.snapshots[1:]...)
+		evicted.mu.Lock()
+		evicted.levels = nil
+		evicted.mu.Unlock()
+	}
+}
+
+// buildLevels hashes the levels of the snapshot the way recalculateTree does.
+func (s *Snapshot) buildLevels() [][][]byte {
+	nodes := make([][]byte, s.leaves.n)
+	for i := range nodes {
+		nodes[i] = s.leaves.at(i)
+	}
+	levels := [][][]byte{nodes}
+	for len(nodes) > 1 {
+		next := make([][]byte, 0, (len(nodes)+1)/2)
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 < len(nodes) {
+				next = append(next, s.hasher.children(nodes[i], nodes[i+1]))
+			} else {
+				next = append(next, s.hasher.unpaired(nodes[i], s.shape))
+			}
+		}
+		levels = append(levels, next)
+		nodes = next
+	}
+	return levels
+}
+
+// Snapshot returns the current version of the tree.
+func (mt *MerkleTree) Snapshot() (*Snapshot, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	if mt.current == nil {
+		return nil, errors.New("merkle tree has not been calculated yet")
+	}
+	return mt.current, nil
+}
+
+// SnapshotAt returns the most recent version of the tree that had treeSize
+// leaves. Versions older than the ones kept by WithSnapshots are not found.
+func (mt *MerkleTree) SnapshotAt(treeSize int) (*Snapshot, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	if mt.current != nil && mt.current.Size() == treeSize {
+		return mt.current, nil
+	}
+	for i := len(mt.history) - 1; i >= 0; i-- {
+		if mt.history[i].Size() == treeSize {
+			return mt.history[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no snapshot of tree size %d", treeSize)
+}
+
+// SnapshotByRoot returns the most recent version of the tree whose root hash
+// is rootHash.
+func (mt *MerkleTree) SnapshotByRoot(rootHash []byte) (*Snapshot, error) {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	if mt.current != nil && bytes.Equal(mt.current.root, rootHash) {
+		return mt.current, nil
+	}
+	for i := len(mt.history) - 1; i >= 0; i-- {
+		if bytes.Equal(mt.history[i].root, rootHash) {
+			return mt.history[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no snapshot with root %x", rootHash)
+}
+
+// GenerateProofAt returns an inclusion proof for the leaf at leafIndex against
+// the most recent version of the tree that had treeSize leaves.
+func (mt *MerkleTree) GenerateProofAt(leafIndex, treeSize int) (*Proof, error) {
+	snapshot, err := mt.SnapshotAt(treeSize)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.GenerateProof(leafIndex)
+}
+
+// recordSnapshot makes the current state of the tree a new version and
+// retires the oldest one beyond the retention limit. It must be called after
+// every change, with leaves appended since the last call not yet in hashes.
+func (mt *MerkleTree) recordSnapshot() {
+	for i := mt.hashes.n; i < len(mt.Leaves); i++ {
+		mt.hashes = mt.hashes.append(mt.Leaves[i].Hash)
+	}
+	if mt.Root == nil {
+		return
+	}
+
+	if mt.levelCache == nil {
+		mt.levelCache = &levelCache{}
+	}
+	mt.current = &Snapshot{
+		cache:  mt.levelCache,
+		hasher: mt.hasher,
+		shape:  mt.shape,
+		leaves: mt.hashes,
+		root:   mt.Root.Hash,
+	}
+	if mt.retain <= 0 {
+		return
+	}
+	mt.history = append(mt.history, mt.current)
+	if len(mt.history) > mt.retain {
+		mt.history[0] = nil
+		mt.history = mt.history[1:]
+	}
+}
+
+// setLeafHash records a changed leaf hash in hashes.
+func (mt *MerkleTree) setLeafHash(index int) {
+	mt.hashes = mt.hashes.set(index, mt.Leaves[index].Hash)
+}
+
+// leafBlockSize is the number of hashes in a block of leafHashes.
+const leafBlockSize = 512
+
+// leafHashes is a list of leaf hashes that versions of a tree share. It is
+// split into blocks so that changing a hash copies one block and the block
+// pointers rather than every hash. Blocks are only ever written past the end
+// of the versions sharing them, so appending copies nothing.
+type leafHashes struct {
+	blocks []*[leafBlockSize][]byte
+	n      int
+}
+
+// at returns the hash at index i.
+func (h leafHashes) at(i int) []byte {
+	return h.blocks[i/leafBlockSize][i%leafBlockSize]
+}
+
+// append returns h with hash added at the end.
+func (h leafHashes) append(hash []byte) leafHashes {
+	if h.n == len(h.blocks)*leafBlockSize {
+		h.blocks = append(h.blocks, new([leafBlockSize][]byte))
+	}
+	h.blocks[h.n/leafBlockSize][h.n%leafBlockSize] = hash
+	h.n++
+	return h
+}
+
+// set returns a copy of h with the hash at index i replaced, leaving h as it
+// is.
+func (h leafHashes) set(i int, hash []byte) leafHashes {
+	blocks := make([]*[leafBlockSize][]byte, len(h.blocks), cap(h.blocks))
+	copy(blocks, h.blocks)
+	block := *blocks[i/leafBlockSize]
+	block[i%leafBlockSize] = hash
+	blocks[i/leafBlockSize] = &block
+	return leafHashes{blocks: blocks, n: h.n}
+}
@@ -0,0 +1,180 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotMatchesTree(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	for size := 1; size <= 17; size++ {
+		mt.AddFile([]byte{byte(size)})
+		snapshot, err := mt.Snapshot()
+		if err != nil {
+			t.Fatalf("Failed to take snapshot: %v", err)
+		}
+		root, _ := mt.RootDigest()
+		if !reflect.DeepEqual(snapshot.RootDigest(), root) || snapshot.Size() != size {
+			t.Fatalf("Snapshot of %d leaves does not match the tree", size)
+		}
+		for i := 0; i < size; i++ {
+			want, _ := mt.GenerateProof(i)
+			got, err := snapshot.GenerateProof(i)
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("Snapshot proof for leaf %d of %d differs from the tree's", i, size)
+			}
+		}
+	}
+}
+
+func TestGenerateProofAt(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	var roots []Digest
+	for i := 0; i < 6; i++ {
+		mt.AddEntry(fmt.Sprint(i), []byte{byte(i)})
+		root, _ := mt.RootDigest()
+		roots = append(roots, root)
+	}
+	// Changing an old leaf must not affect the proofs of earlier versions.
+	mt.UpdateLeaf(0, []byte("changed"))
+	mt.RemoveLeaf(1)
+
+	for size := 1; size <= 5; size++ {
+		for i := 0; i < size; i++ {
+			proof, err := mt.GenerateProofAt(i, size)
+			if err != nil {
+				t.Fatalf("Failed to generate proof for leaf %d at size %d: %v", i, size, err)
+			}
+			if err := VerifyEntryProof(fmt.Sprint(i), []byte{byte(i)}, proof, roots[size-1]); err != nil {
+				t.Errorf("Proof for leaf %d at size %d did not verify: %v", i, size, err)
+			}
+		}
+	}
+
+	// Size 6 now refers to the latest version, where leaf 1 is removed.
+	if _, err := mt.GenerateProofAt(1, 6); err == nil {
+		t.Error("Removed leaf should not be proven in the current version")
+	}
+	if _, err := mt.GenerateProofAt(0, 7); err == nil {
+		t.Error("Proof at an unknown tree size should fail")
+	}
+
+	// The version before the changes is still found by its root.
+	snapshot, err := mt.SnapshotByRoot(roots[5].Hash)
+	if err != nil {
+		t.Fatalf("Failed to find snapshot by root: %v", err)
+	}
+	if snapshot.Size() != 6 || !bytes.Equal(snapshot.RootDigest().Hash, roots[5].Hash) {
+		t.Error("Snapshot found by root does not have that root")
+	}
+	proof, err := snapshot.GenerateProof(1)
+	if err != nil {
+		t.Fatalf("Failed to generate proof from snapshot: %v", err)
+	}
+	if err := VerifyEntryProof("1", []byte{1}, proof, roots[5]); err != nil {
+		t.Errorf("Proof from snapshot did not verify: %v", err)
+	}
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	mt := NewMerkleTree(WithSnapshots(3))
+	for i := 0; i < 6; i++ {
+		mt.AddFile([]byte{byte(i)})
+	}
+	for size := 1; size <= 6; size++ {
+		_, err := mt.SnapshotAt(size)
+		if kept := size > 3; kept != (err == nil) {
+			t.Errorf("Snapshot at size %d: kept %v, error %v", size, kept, err)
+		}
+	}
+
+	// The current version is available even without retention.
+	mt = NewMerkleTree(WithSnapshots(0))
+	mt.AddLeaves([][]byte{{'a'}, {'b'}})
+	mt.AddFile([]byte{'c'})
+	if _, err := mt.SnapshotAt(3); err != nil {
+		t.Errorf("Current snapshot should be available: %v", err)
+	}
+	if _, err := mt.SnapshotAt(2); err == nil {
+		t.Error("Past snapshot should not be kept")
+	}
+}
+
+func TestSnapshotsAcrossLeafBlocks(t *testing.T) {
+	mt := NewMerkleTree()
+	size := 3*leafBlockSize + 1
+	for i := 0; i < size; i++ {
+		mt.AddFile([]byte(fmt.Sprint(i)))
+	}
+	before, _ := mt.Snapshot()
+	for _, i := range []int{0, leafBlockSize - 1, leafBlockSize, size - 1} {
+		mt.UpdateLeaf(i, []byte("changed"))
+	}
+	mt.AddFile([]byte("appended"))
+
+	root := before.RootDigest()
+	for _, i := range []int{0, leafBlockSize - 1, leafBlockSize, size - 1} {
+		proof, err := before.GenerateProof(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyProof([]byte(fmt.Sprint(i)), proof, root); err != nil {
+			t.Errorf("proof of leaf %d from before the updates did not verify: %v", i, err)
+		}
+	}
+	after, _ := mt.Snapshot()
+	proof, _ := after.GenerateProof(leafBlockSize)
+	if current, _ := mt.RootDigest(); VerifyProof([]byte("changed"), proof, current) != nil {
+		t.Error("the current snapshot does not hold the updated leaf")
+	}
+}
+
+func BenchmarkUpdateLeaf(b *testing.B) {
+	mt := NewMerkleTree()
+	leaves := make([][]byte, 100000)
+	for i := range leaves {
+		leaves[i] = []byte(fmt.Sprint(i))
+	}
+	mt.AddLeaves(leaves)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mt.UpdateLeaf(i*7919%len(leaves), []byte(fmt.Sprint(i)))
+	}
+}
+
+func TestSnapshotLevelsCacheIsBounded(t *testing.T) {
+	mt := NewMerkleTree(WithHashMode(HashModeRFC6962))
+	var roots []Digest
+	for i := 0; i < 3*cachedLevels; i++ {
+		mt.AddFile([]byte{byte(i)})
+		root, _ := mt.RootDigest()
+		roots = append(roots, root)
+	}
+	// Proving every version twice rebuilds the levels of those evicted.
+	for round := 0; round < 2; round++ {
+		for size := 1; size <= len(roots); size++ {
+			proof, err := mt.GenerateProofAt(0, size)
+			if err != nil {
+				t.Fatalf("Failed to generate proof at size %d: %v", size, err)
+			}
+			if err := VerifyProof([]byte{0}, proof, roots[size-1]); err != nil {
+				t.Errorf("Proof at size %d did not verify: %v", size, err)
+			}
+		}
+	}
+
+	cached := 0
+	for size := 1; size <= len(roots); size++ {
+		snapshot, _ := mt.SnapshotAt(size)
+		snapshot.mu.Lock()
+		if snapshot.levels != nil {
+			cached++
+		}
+		snapshot.mu.Unlock()
+	}
+	if cached != cachedLevels {
+		t.Errorf("%d snapshots keep their levels, expected %d", cached, cachedLevels)
+	}
+}
@@ -0,0 +1,273 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SparseMerkleTree maps file names to values in a tree with one leaf for every
+// possible key, the key being the hash of the name. Almost all leaves are
+// empty, and empty subtrees have well-known hashes, so only the non-empty
+// nodes are stored. Because every key has a fixed position, the tree can prove
+// that a name is absent as well as present. It is safe for concurrent use.
+type SparseMerkleTree struct {
+	hasher hasher
+	// depth is the number of bits in a key.
+	depth  int
+	values map[string][]byte
+	// nodes holds the hash of every non-empty node.
+	nodes map[sparseNode][]byte
+	// empty[h] is the hash of an empty subtree of height h.
+	empty [][]byte
+	// mu guards values and nodes.
+	mu sync.RWMutex
+}
+
+// sparseNode identifies a node by its height and the key bits above it.
+type sparseNode struct {
+	height int
+	prefix string
+}
+
+// SparseProof proves that a key is or is not in a SparseMerkleTree.
+type SparseProof struct {
+	Algorithm Algorithm
+	Mode      HashMode
+	Key       []byte
+	// Value is the value stored under Key, or nil for a proof of absence.
+	Value []byte
+	// Bitmap has bit h set when the sibling at height h is not empty.
+	Bitmap []byte
+	// Siblings holds the non-empty siblings from the leaf up to the root.
+	Siblings [][]byte
+}
+
+// NewSparseMerkleTree creates an empty SparseMerkleTree. HashModeSorted is
+// taken as HashModeLegacy: an absence proof relies on the position of every
+// sibling, which sorting would let a prover swap.
+func NewSparseMerkleTree(opts ...Option) *SparseMerkleTree {
+	h := newConfig(opts).hasher.positional()
+	depth := h.newHash().Size() * 8
+	return &SparseMerkleTree{
+		hasher: h,
+		depth:  depth,
+		values: make(map[string][]byte),
+		nodes:  make(map[sparseNode][]byte),
+		empty:  emptySubtrees(h, depth),
+	}
+}
+
+// emptySubtrees returns the hash of an empty subtree of every height. An
+// empty leaf is all zeros, which no leaf hash can produce.
+func emptySubtrees(h hasher, depth int) [][]byte {
+	empty := make([][]byte, depth+1)
+	empty[0] = h.tombstone()
+	for i := 1; i <= depth; i++ {
+		empty[i] = h.children(empty[i-1], empty[i-1])
+	}
+	return empty
+}
+
+// Key returns the key a name is stored under.
+func (t *SparseMerkleTree) Key(name string) []byte {
+	return t.hasher.key(name)
+}
+
+// Len returns the number of names in the tree.
+func (t *SparseMerkleTree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.values)
+}
+
+// Get returns the value stored for name.
+func (t *SparseMerkleTree) Get(name string) ([]byte, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	value, ok := t.values[string(t.Key(name))]
+	return value, ok
+}
+
+// Set stores value for name and rehashes the path to the root.
+func (t *SparseMerkleTree) Set(name string, value []byte) {
+	key := t.Key(name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.values[string(key)] = bytes.Clone(value)
+	t.updatePath(key, t.hasher.sparseLeaf(key, value))
+}
+
+// Delete removes name from the tree and rehashes the path to the root.
+func (t *SparseMerkleTree) Delete(name string) {
+	key := t.Key(name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.values[string(key)]; !ok {
+		return
+	}
+	delete(t.values, string(key))
+	t.updatePath(key, t.empty[0])
+}
+
+// RootDigest returns the root of the tree.
+func (t *SparseMerkleTree) RootDigest() Digest {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return Digest{
+		Algorithm: t.hasher.alg,
+		Mode:      t.hasher.mode,
+		Hash:      t.node(t.depth, nil),
+	}
+}
+
+// GenerateProof returns a proof that name is in the tree with its value, or a
+// proof of absence if it is not.
+func (t *SparseMerkleTree) GenerateProof(name string) *SparseProof {
+	key := t.Key(name)
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	proof := &SparseProof{
+		Algorithm: t.hasher.alg,
+		Mode:      t.hasher.mode,
+		Key:       key,
+		Value:     bytes.Clone(t.values[string(key)]),
+		Bitmap:    make([]byte, (t.depth+7)/8),
+	}
+	for h := 0; h < t.depth; h++ {
+		sibling := t.node(h, flipBit(key, t.depth-1-h))
+		if !bytes.Equal(sibling, t.empty[h]) {
+			proof.Bitmap[h/8] |= 1 << (h % 8)
+			proof.Siblings = append(proof.Siblings, sibling)
+		}
+	}
+	return proof
+}
+
+// updatePath sets the leaf for key and recomputes its ancestors.
+func (t *SparseMerkleTree) updatePath(key, leaf []byte) {
+	current := leaf
+	t.setNode(0, key, current)
+	for h := 0; h < t.depth; h++ {
+		sibling := t.node(h, flipBit(key, t.depth-1-h))
+		if bit(key, t.depth-1-h) == 0 {
+			current = t.hasher.children(current, sibling)
+		} else {
+			current = t.hasher.children(sibling, current)
+		}
+		t.setNode(h+1, key, current)
+	}
+}
+
+// node returns the hash of the node at height h above key.
+func (t *SparseMerkleTree) node(h int, key []byte) []byte {
+	if hash, ok := t.nodes[sparseNode{height: h, prefix: keyPrefix(key, h, t.depth)}]; ok {
+		return hash
+	}
+	return t.empty[h]
+}
+
+func (t *SparseMerkleTree) setNode(h int, key, hash []byte) {
+	id := sparseNode{height: h, prefix: keyPrefix(key, h, t.depth)}
+	if bytes.Equal(hash, t.empty[h]) {
+		delete(t.nodes, id)
+	} else {
+		t.nodes[id] = hash
+	}
+}
+
+// VerifySparseProof checks that name is stored in the tree whose root is root
+// with the given value, or, if value is nil, that name is absent from it.
+func VerifySparseProof(name string, value []byte, proof *SparseProof, root Digest) error {
+	if proof == nil {
+		return errors.New("missing proof")
+	}
+	if proof.Algorithm != root.Algorithm {
+		return fmt.Errorf("proof uses hash algorithm %q, root uses %q", proof.Algorithm, root.Algorithm)
+	}
+	if proof.Mode != root.Mode {
+		return fmt.Errorf("proof uses hash mode %v, root uses %v", proof.Mode, root.Mode)
+	}
+	h, err := lookupHasher(proof.Algorithm, proof.Mode)
+	if err != nil {
+		return err
+	}
+	h = h.positional()
+
+	key := h.key(name)
+	depth := len(key) * 8
+	if !bytes.Equal(proof.Key, key) {
+		return fmt.Errorf("proof is for another key than %q", name)
+	}
+	if len(proof.Bitmap) != (depth+7)/8 {
+		return errors.New("proof has an invalid bitmap")
+	}
+
+	empty := emptySubtrees(h, depth)
+	current := empty[0]
+	if value != nil {
+		current = h.sparseLeaf(key, value)
+	}
+	siblings := proof.Siblings
+	for i := 0; i < depth; i++ {
+		sibling := empty[i]
+		if proof.Bitmap[i/8]&(1<<(i%8)) != 0 {
+			if len(siblings) == 0 {
+				return errors.New("proof is too short")
+			}
+			sibling, siblings = siblings[0], siblings[1:]
+		}
+		if bit(key, depth-1-i) == 0 {
+			current = h.children(current, sibling)
+		} else {
+			current = h.children(sibling, current)
+		}
+	}
+	if len(siblings) != 0 {
+		return fmt.Errorf("proof has %d unused siblings", len(siblings))
+	}
+
+	if !bytes.Equal(current, root.Hash) {
+		return errors.New("computed root does not match")
+	}
+	return nil
+}
+
+// key returns the sparse tree key of a name.
+func (h hasher) key(name string) []byte {
+	d := h.newHash()
+	d.Write([]byte(name))
+	return d.Sum(nil)
+}
+
+// sparseLeaf returns the hash of a non-empty sparse tree leaf.
+func (h hasher) sparseLeaf(key, value []byte) []byte {
+	return h.leaf(append(bytes.Clone(key), value...))
+}
+
+// bit returns bit i of key, bit 0 being the most significant bit.
+func bit(key []byte, i int) byte {
+	return key[i/8] >> (7 - i%8) & 1
+}
+
+// flipBit returns a copy of key with bit i flipped.
+func flipBit(key []byte, i int) []byte {
+	flipped := bytes.Clone(key)
+	flipped[i/8] ^= 1 << (7 - i%8)
+	return flipped
+}
+
+// keyPrefix returns the bits of key above height h, the lower h bits cleared.
+func keyPrefix(key []byte, h, depth int) string {
+	if h == depth {
+		return ""
+	}
+	prefix := bytes.Clone(key)
+	full := h / 8
+	for i := len(prefix) - full; i < len(prefix); i++ {
+		prefix[i] = 0
+	}
+	prefix[len(prefix)-1-full] &^= byte(1<<(h%8)) - 1
+	return string(prefix)
+}
@@ -0,0 +1,109 @@
+package merkle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSparseMerkleTreeProofs(t *testing.T) {
+	smt := NewSparseMerkleTree(WithHashMode(HashModeRFC6962))
+	empty := smt.RootDigest()
+	smt.Set("a.txt", []byte("entry a"))
+	smt.Set("b.txt", []byte("entry b"))
+	smt.Set("c.txt", []byte("entry c"))
+	root := smt.RootDigest()
+
+	if bytes.Equal(root.Hash, empty.Hash) {
+		t.Fatal("Root should change when names are added")
+	}
+	if smt.Len() != 3 {
+		t.Errorf("Expected 3 names, got %d", smt.Len())
+	}
+
+	proof := smt.GenerateProof("b.txt")
+	if !bytes.Equal(proof.Value, []byte("entry b")) {
+		t.Errorf("Membership proof should carry the value")
+	}
+	if err := VerifySparseProof("b.txt", []byte("entry b"), proof, root); err != nil {
+		t.Errorf("Membership proof did not verify: %v", err)
+	}
+	if err := VerifySparseProof("b.txt", []byte("entry x"), proof, root); err == nil {
+		t.Error("Membership proof should not verify with another value")
+	}
+	if err := VerifySparseProof("b.txt", nil, proof, root); err == nil {
+		t.Error("Present name should not be proven absent")
+	}
+	if err := VerifySparseProof("a.txt", []byte("entry b"), proof, root); err == nil {
+		t.Error("Membership proof should not verify for another name")
+	}
+
+	absence := smt.GenerateProof("missing.txt")
+	if absence.Value != nil {
+		t.Errorf("Absence proof should not carry a value")
+	}
+	if err := VerifySparseProof("missing.txt", nil, absence, root); err != nil {
+		t.Errorf("Absence proof did not verify: %v", err)
+	}
+	if err := VerifySparseProof("missing.txt", []byte("entry"), absence, root); err == nil {
+		t.Error("Absent name should not be proven present")
+	}
+
+	tampered := *absence
+	tampered.Siblings = append([][]byte(nil), absence.Siblings...)
+	tampered.Siblings[0] = bytes.Repeat([]byte{1}, 32)
+	if err := VerifySparseProof("missing.txt", nil, &tampered, root); err == nil {
+		t.Error("Tampered absence proof should not verify")
+	}
+}
+
+func TestSparseMerkleTreeIsOrderIndependent(t *testing.T) {
+	a := NewSparseMerkleTree()
+	b := NewSparseMerkleTree()
+	names := []string{"one", "two", "three", "four"}
+	for i := range names {
+		a.Set(names[i], []byte(names[i]))
+		b.Set(names[len(names)-1-i], []byte(names[len(names)-1-i]))
+	}
+	if !bytes.Equal(a.RootDigest().Hash, b.RootDigest().Hash) {
+		t.Error("Roots should not depend on insertion order")
+	}
+}
+
+func TestSparseMerkleTreeDelete(t *testing.T) {
+	smt := NewSparseMerkleTree()
+	smt.Set("keep", []byte("1"))
+	before := smt.RootDigest()
+
+	smt.Set("drop", []byte("2"))
+	smt.Delete("drop")
+	if !bytes.Equal(smt.RootDigest().Hash, before.Hash) {
+		t.Error("Deleting a name should restore the previous root")
+	}
+	if _, ok := smt.Get("drop"); ok {
+		t.Error("Deleted name should not be found")
+	}
+	if err := VerifySparseProof("drop", nil, smt.GenerateProof("drop"), smt.RootDigest()); err != nil {
+		t.Errorf("Deleted name should be provably absent: %v", err)
+	}
+
+	smt.Delete("keep")
+	if !bytes.Equal(smt.RootDigest().Hash, NewSparseMerkleTree().RootDigest().Hash) {
+		t.Error("Empty tree should have the empty root")
+	}
+	if len(smt.nodes) != 0 {
+		t.Errorf("Empty tree should not store nodes, found %d", len(smt.nodes))
+	}
+}
+
+func TestSparseMerkleTreeWithHash(t *testing.T) {
+	newHash, _ := LookupAlgorithm(SHA512)
+	smt := NewSparseMerkleTree(WithHash(SHA512, newHash))
+	smt.Set("a", []byte("1"))
+	root := smt.RootDigest()
+	if len(root.Hash) != 64 {
+		t.Errorf("Expected a 64-byte root, got %d bytes", len(root.Hash))
+	}
+	if err := VerifySparseProof("b", nil, smt.GenerateProof("b"), root); err != nil {
+		t.Errorf("Absence proof did not verify: %v", err)
+	}
+}
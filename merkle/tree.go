@@ -0,0 +1,37 @@
+package merkle
+
+// Tree is an append-only log of files that can prove the inclusion of any of
+// them. MerkleTree and MountainRange implement it.
+type Tree interface {
+	// Algorithm returns the hash algorithm the tree was built with.
+	Algorithm() Algorithm
+	// Mode returns the hashing mode the tree was built with.
+	Mode() HashMode
+	// Shape returns the shape of the tree.
+	Shape() Shape
+	// Size returns the number of leaves in the tree.
+	Size() int
+	// AddFile appends a file as a new leaf.
+	AddFile(fileContent []byte) error
+	// AddEntry appends a named file as a new leaf whose data is its
+	// encoded Entry.
+	AddEntry(name string, content []byte) error
+	// RootDigest returns the root of the tree.
+	RootDigest() (Digest, error)
+	// GenerateProof returns an inclusion proof for the leaf at leafIndex.
+	GenerateProof(leafIndex int) (*Proof, error)
+	// GenerateProofWithRoot returns an inclusion proof for the leaf at
+	// leafIndex together with the root it verifies against.
+	GenerateProofWithRoot(leafIndex int) (*Proof, Digest, error)
+	// GetIndicesFromContent returns, in ascending order, the index of
+	// every leaf with the given content.
+	GetIndicesFromContent(content []byte) []int
+	// GetIndicesFromEntry returns, in ascending order, the index of every
+	// leaf holding the named file with the given content.
+	GetIndicesFromEntry(name string, content []byte) []int
+}
+
+var (
+	_ Tree = (*MerkleTree)(nil)
+	_ Tree = (*MountainRange)(nil)
+)
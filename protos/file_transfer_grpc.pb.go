@@ -19,8 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	FileTransfer_UploadFile_FullMethodName   = "/filetransfer.FileTransfer/UploadFile"
-	FileTransfer_DownloadFile_FullMethodName = "/filetransfer.FileTransfer/DownloadFile"
+	FileTransfer_UploadFile_FullMethodName          = "/filetransfer.FileTransfer/UploadFile"
+	FileTransfer_UploadFileStream_FullMethodName    = "/filetransfer.FileTransfer/UploadFileStream"
+	FileTransfer_StartUpload_FullMethodName         = "/filetransfer.FileTransfer/StartUpload"
+	FileTransfer_UploadChunk_FullMethodName         = "/filetransfer.FileTransfer/UploadChunk"
+	FileTransfer_QueryUpload_FullMethodName         = "/filetransfer.FileTransfer/QueryUpload"
+	FileTransfer_CommitUpload_FullMethodName        = "/filetransfer.FileTransfer/CommitUpload"
+	FileTransfer_DownloadFile_FullMethodName        = "/filetransfer.FileTransfer/DownloadFile"
+	FileTransfer_DownloadChunk_FullMethodName       = "/filetransfer.FileTransfer/DownloadChunk"
+	FileTransfer_DownloadFileStream_FullMethodName  = "/filetransfer.FileTransfer/DownloadFileStream"
+	FileTransfer_GetConsistencyProof_FullMethodName = "/filetransfer.FileTransfer/GetConsistencyProof"
+	FileTransfer_DownloadFiles_FullMethodName       = "/filetransfer.FileTransfer/DownloadFiles"
+	FileTransfer_GetTreeState_FullMethodName        = "/filetransfer.FileTransfer/GetTreeState"
+	FileTransfer_ListFiles_FullMethodName           = "/filetransfer.FileTransfer/ListFiles"
+	FileTransfer_GetSubtreeHashes_FullMethodName    = "/filetransfer.FileTransfer/GetSubtreeHashes"
+	FileTransfer_GetLeafEntries_FullMethodName      = "/filetransfer.FileTransfer/GetLeafEntries"
+	FileTransfer_DumpTree_FullMethodName            = "/filetransfer.FileTransfer/DumpTree"
 )
 
 // FileTransferClient is the client API for FileTransfer service.
@@ -28,7 +42,21 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type FileTransferClient interface {
 	UploadFile(ctx context.Context, in *FileData, opts ...grpc.CallOption) (*UploadStatus, error)
+	UploadFileStream(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_UploadFileStreamClient, error)
+	StartUpload(ctx context.Context, in *UploadHeader, opts ...grpc.CallOption) (*UploadSession, error)
+	UploadChunk(ctx context.Context, in *UploadChunkRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	QueryUpload(ctx context.Context, in *UploadSessionID, opts ...grpc.CallOption) (*UploadSession, error)
+	CommitUpload(ctx context.Context, in *UploadSessionID, opts ...grpc.CallOption) (*UploadStatus, error)
 	DownloadFile(ctx context.Context, in *FileName, opts ...grpc.CallOption) (*FileDownloadResponse, error)
+	DownloadChunk(ctx context.Context, in *ChunkRequest, opts ...grpc.CallOption) (*ChunkResponse, error)
+	DownloadFileStream(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (FileTransfer_DownloadFileStreamClient, error)
+	GetConsistencyProof(ctx context.Context, in *ConsistencyRequest, opts ...grpc.CallOption) (*ConsistencyResponse, error)
+	DownloadFiles(ctx context.Context, in *FileNames, opts ...grpc.CallOption) (*BatchDownloadResponse, error)
+	GetTreeState(ctx context.Context, in *TreeStateRequest, opts ...grpc.CallOption) (*TreeState, error)
+	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
+	GetSubtreeHashes(ctx context.Context, in *SubtreeHashesRequest, opts ...grpc.CallOption) (*SubtreeHashesResponse, error)
+	GetLeafEntries(ctx context.Context, in *LeafRange, opts ...grpc.CallOption) (*LeafEntries, error)
+	DumpTree(ctx context.Context, in *DumpTreeRequest, opts ...grpc.CallOption) (*TreeDump, error)
 }
 
 type fileTransferClient struct {
@@ -48,6 +76,76 @@ func (c *fileTransferClient) UploadFile(ctx context.Context, in *FileData, opts
 	return out, nil
 }
 
+func (c *fileTransferClient) UploadFileStream(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_UploadFileStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[0], FileTransfer_UploadFileStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferUploadFileStreamClient{stream}
+	return x, nil
+}
+
+type FileTransfer_UploadFileStreamClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*UploadStatus, error)
+	grpc.ClientStream
+}
+
+type fileTransferUploadFileStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferUploadFileStreamClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileTransferUploadFileStreamClient) CloseAndRecv() (*UploadStatus, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileTransferClient) StartUpload(ctx context.Context, in *UploadHeader, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, FileTransfer_StartUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) UploadChunk(ctx context.Context, in *UploadChunkRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, FileTransfer_UploadChunk_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) QueryUpload(ctx context.Context, in *UploadSessionID, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, FileTransfer_QueryUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) CommitUpload(ctx context.Context, in *UploadSessionID, opts ...grpc.CallOption) (*UploadStatus, error) {
+	out := new(UploadStatus)
+	err := c.cc.Invoke(ctx, FileTransfer_CommitUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileTransferClient) DownloadFile(ctx context.Context, in *FileName, opts ...grpc.CallOption) (*FileDownloadResponse, error) {
 	out := new(FileDownloadResponse)
 	err := c.cc.Invoke(ctx, FileTransfer_DownloadFile_FullMethodName, in, out, opts...)
@@ -57,12 +155,130 @@ func (c *fileTransferClient) DownloadFile(ctx context.Context, in *FileName, opt
 	return out, nil
 }
 
+func (c *fileTransferClient) DownloadChunk(ctx context.Context, in *ChunkRequest, opts ...grpc.CallOption) (*ChunkResponse, error) {
+	out := new(ChunkResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_DownloadChunk_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) DownloadFileStream(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (FileTransfer_DownloadFileStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[1], FileTransfer_DownloadFileStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferDownloadFileStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileTransfer_DownloadFileStreamClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type fileTransferDownloadFileStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferDownloadFileStreamClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileTransferClient) GetConsistencyProof(ctx context.Context, in *ConsistencyRequest, opts ...grpc.CallOption) (*ConsistencyResponse, error) {
+	out := new(ConsistencyResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_GetConsistencyProof_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) DownloadFiles(ctx context.Context, in *FileNames, opts ...grpc.CallOption) (*BatchDownloadResponse, error) {
+	out := new(BatchDownloadResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_DownloadFiles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) GetTreeState(ctx context.Context, in *TreeStateRequest, opts ...grpc.CallOption) (*TreeState, error) {
+	out := new(TreeState)
+	err := c.cc.Invoke(ctx, FileTransfer_GetTreeState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
+	out := new(ListFilesResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_ListFiles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) GetSubtreeHashes(ctx context.Context, in *SubtreeHashesRequest, opts ...grpc.CallOption) (*SubtreeHashesResponse, error) {
+	out := new(SubtreeHashesResponse)
+	err := c.cc.Invoke(ctx, FileTransfer_GetSubtreeHashes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) GetLeafEntries(ctx context.Context, in *LeafRange, opts ...grpc.CallOption) (*LeafEntries, error) {
+	out := new(LeafEntries)
+	err := c.cc.Invoke(ctx, FileTransfer_GetLeafEntries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileTransferClient) DumpTree(ctx context.Context, in *DumpTreeRequest, opts ...grpc.CallOption) (*TreeDump, error) {
+	out := new(TreeDump)
+	err := c.cc.Invoke(ctx, FileTransfer_DumpTree_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileTransferServer is the server API for FileTransfer service.
 // All implementations must embed UnimplementedFileTransferServer
 // for forward compatibility
 type FileTransferServer interface {
 	UploadFile(context.Context, *FileData) (*UploadStatus, error)
+	UploadFileStream(FileTransfer_UploadFileStreamServer) error
+	StartUpload(context.Context, *UploadHeader) (*UploadSession, error)
+	UploadChunk(context.Context, *UploadChunkRequest) (*UploadSession, error)
+	QueryUpload(context.Context, *UploadSessionID) (*UploadSession, error)
+	CommitUpload(context.Context, *UploadSessionID) (*UploadStatus, error)
 	DownloadFile(context.Context, *FileName) (*FileDownloadResponse, error)
+	DownloadChunk(context.Context, *ChunkRequest) (*ChunkResponse, error)
+	DownloadFileStream(*DownloadRequest, FileTransfer_DownloadFileStreamServer) error
+	GetConsistencyProof(context.Context, *ConsistencyRequest) (*ConsistencyResponse, error)
+	DownloadFiles(context.Context, *FileNames) (*BatchDownloadResponse, error)
+	GetTreeState(context.Context, *TreeStateRequest) (*TreeState, error)
+	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
+	GetSubtreeHashes(context.Context, *SubtreeHashesRequest) (*SubtreeHashesResponse, error)
+	GetLeafEntries(context.Context, *LeafRange) (*LeafEntries, error)
+	DumpTree(context.Context, *DumpTreeRequest) (*TreeDump, error)
 	mustEmbedUnimplementedFileTransferServer()
 }
 
@@ -73,9 +289,51 @@ type UnimplementedFileTransferServer struct {
 func (UnimplementedFileTransferServer) UploadFile(context.Context, *FileData) (*UploadStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedFileTransferServer) UploadFileStream(FileTransfer_UploadFileStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFileStream not implemented")
+}
+func (UnimplementedFileTransferServer) StartUpload(context.Context, *UploadHeader) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartUpload not implemented")
+}
+func (UnimplementedFileTransferServer) UploadChunk(context.Context, *UploadChunkRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadChunk not implemented")
+}
+func (UnimplementedFileTransferServer) QueryUpload(context.Context, *UploadSessionID) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryUpload not implemented")
+}
+func (UnimplementedFileTransferServer) CommitUpload(context.Context, *UploadSessionID) (*UploadStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitUpload not implemented")
+}
 func (UnimplementedFileTransferServer) DownloadFile(context.Context, *FileName) (*FileDownloadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
 }
+func (UnimplementedFileTransferServer) DownloadChunk(context.Context, *ChunkRequest) (*ChunkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadChunk not implemented")
+}
+func (UnimplementedFileTransferServer) DownloadFileStream(*DownloadRequest, FileTransfer_DownloadFileStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFileStream not implemented")
+}
+func (UnimplementedFileTransferServer) GetConsistencyProof(context.Context, *ConsistencyRequest) (*ConsistencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConsistencyProof not implemented")
+}
+func (UnimplementedFileTransferServer) DownloadFiles(context.Context, *FileNames) (*BatchDownloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadFiles not implemented")
+}
+func (UnimplementedFileTransferServer) GetTreeState(context.Context, *TreeStateRequest) (*TreeState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTreeState not implemented")
+}
+func (UnimplementedFileTransferServer) ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFiles not implemented")
+}
+func (UnimplementedFileTransferServer) GetSubtreeHashes(context.Context, *SubtreeHashesRequest) (*SubtreeHashesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubtreeHashes not implemented")
+}
+func (UnimplementedFileTransferServer) GetLeafEntries(context.Context, *LeafRange) (*LeafEntries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeafEntries not implemented")
+}
+func (UnimplementedFileTransferServer) DumpTree(context.Context, *DumpTreeRequest) (*TreeDump, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DumpTree not implemented")
+}
 func (UnimplementedFileTransferServer) mustEmbedUnimplementedFileTransferServer() {}
 
 // UnsafeFileTransferServer may be embedded to opt out of forward compatibility for this service.
@@ -107,6 +365,104 @@ func _FileTransfer_UploadFile_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileTransfer_UploadFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileTransferServer).UploadFileStream(&fileTransferUploadFileStreamServer{stream})
+}
+
+type FileTransfer_UploadFileStreamServer interface {
+	SendAndClose(*UploadStatus) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type fileTransferUploadFileStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferUploadFileStreamServer) SendAndClose(m *UploadStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileTransferUploadFileStreamServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileTransfer_StartUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadHeader)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).StartUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_StartUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).StartUpload(ctx, req.(*UploadHeader))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_UploadChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).UploadChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_UploadChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).UploadChunk(ctx, req.(*UploadChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_QueryUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).QueryUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_QueryUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).QueryUpload(ctx, req.(*UploadSessionID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_CommitUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).CommitUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_CommitUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).CommitUpload(ctx, req.(*UploadSessionID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileTransfer_DownloadFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FileName)
 	if err := dec(in); err != nil {
@@ -125,6 +481,171 @@ func _FileTransfer_DownloadFile_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileTransfer_DownloadChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).DownloadChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_DownloadChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).DownloadChunk(ctx, req.(*ChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_DownloadFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileTransferServer).DownloadFileStream(m, &fileTransferDownloadFileStreamServer{stream})
+}
+
+type FileTransfer_DownloadFileStreamServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type fileTransferDownloadFileStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferDownloadFileStreamServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileTransfer_GetConsistencyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsistencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).GetConsistencyProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_GetConsistencyProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).GetConsistencyProof(ctx, req.(*ConsistencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_DownloadFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FileNames)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).DownloadFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_DownloadFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).DownloadFiles(ctx, req.(*FileNames))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_GetTreeState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TreeStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).GetTreeState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_GetTreeState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).GetTreeState(ctx, req.(*TreeStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_ListFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).ListFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_ListFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).ListFiles(ctx, req.(*ListFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_GetSubtreeHashes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubtreeHashesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).GetSubtreeHashes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_GetSubtreeHashes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).GetSubtreeHashes(ctx, req.(*SubtreeHashesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_GetLeafEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeafRange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).GetLeafEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_GetLeafEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).GetLeafEntries(ctx, req.(*LeafRange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileTransfer_DumpTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DumpTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileTransferServer).DumpTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileTransfer_DumpTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileTransferServer).DumpTree(ctx, req.(*DumpTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FileTransfer_ServiceDesc is the grpc.ServiceDesc for FileTransfer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -136,11 +657,70 @@ var FileTransfer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UploadFile",
 			Handler:    _FileTransfer_UploadFile_Handler,
 		},
+		{
+			MethodName: "StartUpload",
+			Handler:    _FileTransfer_StartUpload_Handler,
+		},
+		{
+			MethodName: "UploadChunk",
+			Handler:    _FileTransfer_UploadChunk_Handler,
+		},
+		{
+			MethodName: "QueryUpload",
+			Handler:    _FileTransfer_QueryUpload_Handler,
+		},
+		{
+			MethodName: "CommitUpload",
+			Handler:    _FileTransfer_CommitUpload_Handler,
+		},
 		{
 			MethodName: "DownloadFile",
 			Handler:    _FileTransfer_DownloadFile_Handler,
 		},
+		{
+			MethodName: "DownloadChunk",
+			Handler:    _FileTransfer_DownloadChunk_Handler,
+		},
+		{
+			MethodName: "GetConsistencyProof",
+			Handler:    _FileTransfer_GetConsistencyProof_Handler,
+		},
+		{
+			MethodName: "DownloadFiles",
+			Handler:    _FileTransfer_DownloadFiles_Handler,
+		},
+		{
+			MethodName: "GetTreeState",
+			Handler:    _FileTransfer_GetTreeState_Handler,
+		},
+		{
+			MethodName: "ListFiles",
+			Handler:    _FileTransfer_ListFiles_Handler,
+		},
+		{
+			MethodName: "GetSubtreeHashes",
+			Handler:    _FileTransfer_GetSubtreeHashes_Handler,
+		},
+		{
+			MethodName: "GetLeafEntries",
+			Handler:    _FileTransfer_GetLeafEntries_Handler,
+		},
+		{
+			MethodName: "DumpTree",
+			Handler:    _FileTransfer_DumpTree_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadFileStream",
+			Handler:       _FileTransfer_UploadFileStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadFileStream",
+			Handler:       _FileTransfer_DownloadFileStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "protos/file_transfer.proto",
 }
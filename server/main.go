@@ -1,36 +1,152 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"database/sql"
-	_ "github.com/lib/pq" // The underscore is important
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
 	merkleTree "go-merkle-file-transfer/merkle"
 	pb "go-merkle-file-transfer/protos"
+	"io"
 	"log"
+	"math"
+	mathrand "math/rand"
 	"net"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
 type FileTransferServer struct {
 	pb.UnimplementedFileTransferServer
-	MerkleTree *merkleTree.MerkleTree
-	DB         *sql.DB
+	// MerkleTree is a *merkleTree.MerkleTree unless another backend was
+	// selected through MERKLE_BACKEND.
+	MerkleTree merkleTree.Tree
+	// Names maps every file name to its entry so that missing files can be
+	// proven absent.
+	Names *merkleTree.SparseMerkleTree
+	DB    *sql.DB
+	// SessionTTL is how long an upload session lives without receiving a
+	// chunk.
+	SessionTTL time.Duration
+	// Debug enables DumpTree and GetTreeState, which hand out every leaf of
+	// the tree and are refused otherwise.
+	Debug bool
+	// mu serializes changes to the trees so that entries[i] is always the
+	// encoded entry of leaf i.
+	mu sync.Mutex
+	// entries holds the encoded entry of every leaf, for peers repairing
+	// their trees.
+	entries [][]byte
 }
 
 var merkletree = merkleTree.NewMerkleTree()
 
+// merkleOptions returns the tree options shared with the clients. The hash
+// algorithm defaults to SHA-256 and can be changed through MERKLE_HASH.
+func merkleOptions() []merkleTree.Option {
+	alg := merkleTree.SHA256
+	if name, ok := os.LookupEnv("MERKLE_HASH"); ok {
+		alg = merkleTree.Algorithm(name)
+	}
+	newHash, ok := merkleTree.LookupAlgorithm(alg)
+	if !ok {
+		log.Fatalf("Unknown hash algorithm: %s", alg)
+	}
+	return []merkleTree.Option{
+		merkleTree.WithHash(alg, newHash),
+		merkleTree.WithHashMode(hashMode()),
+		merkleTree.WithShape(treeShape()),
+	}
+}
+
+// hashMode returns the hashing mode selected through MERKLE_HASH_MODE:
+// "rfc6962" (the default), "legacy" for trees built before domain separation,
+// or "sorted" for the sorted-pair trees of the first version.
+func hashMode() merkleTree.HashMode {
+	name, _ := os.LookupEnv("MERKLE_HASH_MODE")
+	switch name {
+	case "", "rfc6962":
+		return merkleTree.HashModeRFC6962
+	case "legacy":
+		return merkleTree.HashModeLegacy
+	case "sorted":
+		return merkleTree.HashModeSorted
+	default:
+		log.Fatalf("Unknown Merkle hash mode: %s", name)
+		return 0
+	}
+}
+
+// treeShape returns the tree shape selected through MERKLE_SHAPE: "promote"
+// (the default, format 2) or "duplicate" to keep format 1 roots.
+func treeShape() merkleTree.Shape {
+	name, _ := os.LookupEnv("MERKLE_SHAPE")
+	switch name {
+	case "", "promote":
+		return merkleTree.ShapePromote
+	case "duplicate":
+		return merkleTree.ShapeDuplicate
+	default:
+		log.Fatalf("Unknown Merkle tree shape: %s", name)
+		return 0
+	}
+}
+
+// newTree returns the tree backend selected through MERKLE_BACKEND: "merkle"
+// (the default) or "mmr" for a Merkle Mountain Range.
+func newTree() merkleTree.Tree {
+	backend, _ := os.LookupEnv("MERKLE_BACKEND")
+	switch backend {
+	case "", "merkle":
+		return merkleTree.NewMerkleTree(merkleOptions()...)
+	case "mmr":
+		if treeShape() != merkleTree.ShapePromote {
+			log.Fatalf("The mmr backend only supports the promote shape")
+		}
+		return merkleTree.NewMountainRange(merkleOptions()...)
+	default:
+		log.Fatalf("Unknown Merkle backend: %s", backend)
+		return nil
+	}
+}
+
+// fullTree returns the tree for the RPCs that only a MerkleTree supports.
+func (s *FileTransferServer) fullTree() (*merkleTree.MerkleTree, error) {
+	mt, ok := s.MerkleTree.(*merkleTree.MerkleTree)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "Not supported by the %T backend", s.MerkleTree)
+	}
+	return mt, nil
+}
+
 func (s *FileTransferServer) UploadFile(ctx context.Context, in *pb.FileData) (*pb.UploadStatus, error) {
 	log.Printf("Received UploadFile request for file: %s\n", in.GetName())
 
-	// Update the Merkle tree
-	s.MerkleTree.AddFile(in.GetContent())
+	// The tree gets a leaf binding the name to the content
+	w, err := merkleTree.NewEntryWriter(s.MerkleTree.Algorithm(), s.MerkleTree.Mode(), in.GetName())
+	if err != nil {
+		log.Printf("Failed to hash file: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	w.Write(in.GetContent())
 
 	// Prepare SQL statement to insert file content and metadata into the database
-	stmt, err := s.DB.Prepare("INSERT INTO file_storage(file_name, file_content) VALUES($1, $2)")
+	stmt, err := s.DB.Prepare("INSERT INTO file_storage(file_name, file_content, chunk_hashes) VALUES($1, $2, $3)")
 	if err != nil {
 		log.Printf("Failed to prepare SQL statement: %v", err)
 		return nil, status.Errorf(codes.Internal, "Internal Server Error")
@@ -38,18 +154,1018 @@ func (s *FileTransferServer) UploadFile(ctx context.Context, in *pb.FileData) (*
 	defer stmt.Close()
 
 	// Execute the SQL statement
-	_, err = stmt.Exec(in.GetName(), in.GetContent())
+	_, err = stmt.Exec(in.GetName(), in.GetContent(), bytes.Join(w.ChunkHashes(), nil))
+	if isUniqueViolation(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "File %s already exists", in.GetName())
+	}
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+
+	// The file is stored, so it can go into the tree
+	s.mu.Lock()
+	leafIndex := s.addEntry(w.Entry().Encode())
+	root, err := s.MerkleTree.RootDigest()
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("Failed to compute root: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+
+	return &pb.UploadStatus{
+		Success:   true,
+		LeafIndex: uint64(leafIndex),
+		TreeHead:  treeHeadToPB(root, leafIndex+1),
+	}, nil
+}
+
+func (s *FileTransferServer) UploadFileStream(stream pb.FileTransfer_UploadFileStreamServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Missing upload header: %v", err)
+	}
+	header := req.GetHeader()
+	if header == nil {
+		return status.Errorf(codes.InvalidArgument, "The first message must be the upload header")
+	}
+	log.Printf("Received UploadFileStream request for file: %s (%d bytes)\n", header.GetName(), header.GetSize())
+	if header.GetSize() > math.MaxInt64 {
+		return status.Errorf(codes.InvalidArgument, "File size %d is too large", header.GetSize())
+	}
+
+	w, err := merkleTree.NewEntryWriter(s.MerkleTree.Algorithm(), s.MerkleTree.Mode(), header.GetName())
+	if err != nil {
+		log.Printf("Failed to hash file: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+
+	// Chunks are staged in the database as they arrive and only assembled
+	// there, so the server never holds the whole file. Nothing is visible
+	// to other transactions until the file is complete.
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	defer tx.Rollback()
+	uploadID, err := newUploadID()
+	if err != nil {
+		log.Printf("Failed to create upload ID: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	stmt, err := tx.Prepare("INSERT INTO upload_chunks(upload_id, chunk_offset, chunk_content) VALUES($1, $2, $3)")
+	if err != nil {
+		log.Printf("Failed to prepare SQL statement: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	defer stmt.Close()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetHeader() != nil {
+			return status.Errorf(codes.InvalidArgument, "Only the first message can be the upload header")
+		}
+		chunk := req.GetChunk()
+		if len(chunk) == 0 {
+			continue
+		}
+		if w.Size()+uint64(len(chunk)) > header.GetSize() {
+			return status.Errorf(codes.InvalidArgument, "File is larger than the %d bytes announced", header.GetSize())
+		}
+		if _, err := stmt.Exec(uploadID, w.Size(), chunk); err != nil {
+			log.Printf("Failed to execute SQL statement: %v", err)
+			return status.Errorf(codes.Internal, "Internal Server Error")
+		}
+		w.Write(chunk)
+	}
+	if w.Size() != header.GetSize() {
+		return status.Errorf(codes.InvalidArgument, "File has %d bytes, %d were announced", w.Size(), header.GetSize())
+	}
+
+	if err := s.assembleFile(tx, header.GetName(), uploadID, w.ChunkHashes()); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+
+	// The file is stored, so it can go into the tree
+	s.mu.Lock()
+	leafIndex := s.addEntry(w.Entry().Encode())
+	root, err := s.MerkleTree.RootDigest()
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("Failed to compute root: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	return stream.SendAndClose(&pb.UploadStatus{
+		Success:   true,
+		LeafIndex: uint64(leafIndex),
+		TreeHead:  treeHeadToPB(root, leafIndex+1),
+	})
+}
+
+func (s *FileTransferServer) StartUpload(ctx context.Context, in *pb.UploadHeader) (*pb.UploadSession, error) {
+	log.Printf("Received StartUpload request for file: %s (%d bytes)\n", in.GetName(), in.GetSize())
+	// Sizes and offsets are stored as bigint.
+	if in.GetSize() > math.MaxInt64 {
+		return nil, status.Errorf(codes.InvalidArgument, "File size %d is too large", in.GetSize())
+	}
+
+	var exists bool
+	err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM file_storage WHERE file_name=$1)", in.GetName()).Scan(&exists)
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if exists {
+		return nil, status.Errorf(codes.AlreadyExists, "File %s already exists", in.GetName())
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		log.Printf("Failed to create upload ID: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	session := &pb.UploadSession{UploadId: uploadID, Name: in.GetName(), Size: in.GetSize()}
+	err = s.DB.QueryRow(`
+    INSERT INTO upload_sessions(upload_id, file_name, file_size, expires_at)
+    VALUES($1, $2, $3, now() + $4 * interval '1 second')
+    RETURNING extract(epoch FROM expires_at)::bigint`,
+		uploadID, in.GetName(), int64(in.GetSize()), int64(s.SessionTTL.Seconds())).Scan(&session.ExpireTime)
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	return session, nil
+}
+
+func (s *FileTransferServer) UploadChunk(ctx context.Context, in *pb.UploadChunkRequest) (*pb.UploadSession, error) {
+	log.Printf("Received UploadChunk request for upload %s at offset %d\n", in.GetUploadId(), in.GetOffset())
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	defer tx.Rollback()
+	session, err := lockSession(tx, in.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Chunks must arrive in order, so the received bytes are always a
+	// prefix of the file. A chunk the session already holds, resent after
+	// a lost acknowledgement, is acknowledged again.
+	chunk := in.GetChunk()
+	end := in.GetOffset() + uint64(len(chunk))
+	switch {
+	case end <= session.GetOffset():
+		return session, nil
+	case in.GetOffset() != session.GetOffset():
+		return nil, status.Errorf(codes.FailedPrecondition, "Expected a chunk at offset %d, got %d", session.GetOffset(), in.GetOffset())
+	case end > session.GetSize():
+		return nil, status.Errorf(codes.InvalidArgument, "File is larger than the %d bytes announced", session.GetSize())
+	}
+
+	_, err = tx.Exec("INSERT INTO upload_chunks(upload_id, chunk_offset, chunk_content) VALUES($1, $2, $3)",
+		in.GetUploadId(), int64(in.GetOffset()), chunk)
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	err = tx.QueryRow(`
+    UPDATE upload_sessions SET received = $2, expires_at = now() + $3 * interval '1 second'
+    WHERE upload_id = $1
+    RETURNING extract(epoch FROM expires_at)::bigint`,
+		in.GetUploadId(), int64(end), int64(s.SessionTTL.Seconds())).Scan(&session.ExpireTime)
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	session.Offset = end
+	return session, nil
+}
+
+func (s *FileTransferServer) QueryUpload(ctx context.Context, in *pb.UploadSessionID) (*pb.UploadSession, error) {
+	log.Printf("Received QueryUpload request for upload: %s\n", in.GetUploadId())
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	defer tx.Rollback()
+	return lockSession(tx, in.GetUploadId())
+}
+
+func (s *FileTransferServer) CommitUpload(ctx context.Context, in *pb.UploadSessionID) (*pb.UploadStatus, error) {
+	log.Printf("Received CommitUpload request for upload: %s\n", in.GetUploadId())
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	defer tx.Rollback()
+	session, err := lockSession(tx, in.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+	if session.GetOffset() != session.GetSize() {
+		return nil, status.Errorf(codes.FailedPrecondition, "Received %d of %d bytes", session.GetOffset(), session.GetSize())
+	}
+
+	// Hash the staged chunks one at a time before assembling them
+	w, err := merkleTree.NewEntryWriter(s.MerkleTree.Algorithm(), s.MerkleTree.Mode(), session.GetName())
+	if err != nil {
+		log.Printf("Failed to hash file: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if err := hashChunks(tx, in.GetUploadId(), w); err != nil {
+		log.Printf("Failed to hash staged chunks: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if err := s.assembleFile(tx, session.GetName(), in.GetUploadId(), w.ChunkHashes()); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("DELETE FROM upload_sessions WHERE upload_id = $1", in.GetUploadId()); err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+
+	// The file is stored, so it can go into the tree
+	s.mu.Lock()
+	leafIndex := s.addEntry(w.Entry().Encode())
+	root, err := s.MerkleTree.RootDigest()
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("Failed to compute root: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	return &pb.UploadStatus{
+		Success:   true,
+		LeafIndex: uint64(leafIndex),
+		TreeHead:  treeHeadToPB(root, leafIndex+1),
+	}, nil
+}
+
+// lockSession reads an upload session and locks it until tx ends. Expired
+// sessions are not found, even before they are collected.
+func lockSession(tx *sql.Tx, uploadID string) (*pb.UploadSession, error) {
+	session := &pb.UploadSession{UploadId: uploadID}
+	var size, received int64
+	err := tx.QueryRow(`
+    SELECT file_name, file_size, received, extract(epoch FROM expires_at)::bigint
+    FROM upload_sessions WHERE upload_id = $1 AND expires_at > now()
+    FOR UPDATE`, uploadID).Scan(&session.Name, &size, &received, &session.ExpireTime)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "Upload %s not found or expired", uploadID)
+	}
 	if err != nil {
 		log.Printf("Failed to execute SQL statement: %v", err)
 		return nil, status.Errorf(codes.Internal, "Internal Server Error")
 	}
+	session.Size, session.Offset = uint64(size), uint64(received)
+	return session, nil
+}
+
+// hashChunks writes the chunks staged under uploadID to w in order.
+func hashChunks(tx *sql.Tx, uploadID string, w io.Writer) error {
+	rows, err := tx.Query("SELECT chunk_content FROM upload_chunks WHERE upload_id = $1 ORDER BY chunk_offset", uploadID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var chunk []byte
+		if err := rows.Scan(&chunk); err != nil {
+			return err
+		}
+		w.Write(chunk)
+	}
+	return rows.Err()
+}
+
+// collectUploads drops the expired upload sessions and their chunks every
+// interval.
+func (s *FileTransferServer) collectUploads(interval time.Duration) {
+	for range time.Tick(interval) {
+		_, err := s.DB.Exec(`
+    WITH expired AS (DELETE FROM upload_sessions WHERE expires_at <= now() RETURNING upload_id)
+    DELETE FROM upload_chunks WHERE upload_id IN (SELECT upload_id FROM expired)`)
+		if err != nil {
+			log.Printf("Failed to collect expired uploads: %v", err)
+		}
+	}
+}
+
+// sessionTTL returns how long an upload session lives without receiving a
+// chunk, set through UPLOAD_SESSION_TTL.
+func sessionTTL() time.Duration {
+	value, ok := os.LookupEnv("UPLOAD_SESSION_TTL")
+	if !ok {
+		return 24 * time.Hour
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < time.Second {
+		log.Fatalf("Invalid upload session TTL: %s", value)
+	}
+	return ttl
+}
 
-	return &pb.UploadStatus{Success: true}, nil
+// debugRPCs reports whether DEBUG_RPCS enables the debugging RPCs.
+func debugRPCs() bool {
+	value, ok := os.LookupEnv("DEBUG_RPCS")
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Invalid DEBUG_RPCS: %s", value)
+	}
+	return enabled
+}
+
+// newUploadID returns a random identifier for staging the chunks of an
+// upload.
+func newUploadID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// assembleFile stores the chunks staged under uploadID as the file called
+// name, in chunk order, and drops them.
+func (s *FileTransferServer) assembleFile(tx *sql.Tx, name, uploadID string, chunkHashes [][]byte) error {
+	_, err := tx.Exec(`
+    INSERT INTO file_storage(file_name, file_content, chunk_hashes)
+    SELECT $1, COALESCE(string_agg(chunk_content, ''::bytea ORDER BY chunk_offset), ''::bytea), $3
+    FROM upload_chunks WHERE upload_id = $2`, name, uploadID, bytes.Join(chunkHashes, nil))
+	if isUniqueViolation(err) {
+		// Another upload of the same name was stored first.
+		return status.Errorf(codes.AlreadyExists, "File %s already exists", name)
+	}
+	if err != nil {
+		log.Printf("Failed to store file %s: %v", name, err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if _, err := tx.Exec("DELETE FROM upload_chunks WHERE upload_id = $1", uploadID); err != nil {
+		log.Printf("Failed to drop staged chunks: %v", err)
+		return status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is Postgres refusing a duplicate key.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation"
 }
 
 func (s *FileTransferServer) DownloadFile(ctx context.Context, in *pb.FileName) (*pb.FileDownloadResponse, error) {
 	log.Printf("Received DownloadFile request for file: %s\n", in.GetName())
 
+	fileContent, err := s.fetchFile(in.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	proof, root, err := s.fileProof(in.GetName(), fileContent)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.FileDownloadResponse{
+		Content:     fileContent,
+		MerkleProof: proofToPB(proof),
+		TreeHead:    treeHeadToPB(root, proof.TreeSize),
+	}, nil
+
+}
+
+func (s *FileTransferServer) DownloadChunk(ctx context.Context, in *pb.ChunkRequest) (*pb.ChunkResponse, error) {
+	log.Printf("Received DownloadChunk request for chunk %d of file: %s\n", in.GetIndex(), in.GetName())
+
+	size, err := s.fileSize(in.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetIndex() >= uint64(merkleTree.NumChunks(size)) {
+		return nil, status.Errorf(codes.OutOfRange, "Chunk %d out of range", in.GetIndex())
+	}
+
+	chunkTree, err := s.chunkTree(in.GetName(), size)
+	if err != nil {
+		return nil, err
+	}
+	entry := chunkTree.Entry(in.GetName())
+	leafIndex, err := latestLeaf(in.GetName(), s.MerkleTree.GetIndicesFromContent(entry.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	fileProof, root, err := s.MerkleTree.GenerateProofWithRoot(leafIndex)
+	if err != nil {
+		log.Printf("Error generating Merkle proof: %v", err)
+		return nil, status.Errorf(codes.Internal, "Could not generate Merkle proof")
+	}
+	chunkProof, err := chunkTree.GenerateProof(int(in.GetIndex()))
+	if err != nil {
+		log.Printf("Error generating chunk proof: %v", err)
+		return nil, status.Errorf(codes.Internal, "Could not generate chunk proof")
+	}
+
+	start := in.GetIndex() * merkleTree.ChunkSize
+	end := start + merkleTree.ChunkSize
+	if end > size {
+		end = size
+	}
+	chunk, err := io.ReadAll(s.fileReader(in.GetName(), start, end))
+	if err != nil {
+		log.Printf("Failed to read chunk %d of %s: %v", in.GetIndex(), in.GetName(), err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+
+	return &pb.ChunkResponse{
+		Chunk: chunk,
+		Entry: &pb.FileEntry{
+			Name:        entry.Name,
+			Size:        entry.Size,
+			ContentHash: entry.ContentHash,
+		},
+		ChunkProof: proofToPB(chunkProof),
+		FileProof:  proofToPB(fileProof),
+		TreeHead:   treeHeadToPB(root, fileProof.TreeSize),
+	}, nil
+}
+
+func (s *FileTransferServer) DownloadFileStream(in *pb.DownloadRequest, stream pb.FileTransfer_DownloadFileStreamServer) error {
+	log.Printf("Received DownloadFileStream request for bytes %d+%d of file: %s\n", in.GetOffset(), in.GetLength(), in.GetName())
+
+	size, err := s.fileSize(in.GetName())
+	if err != nil {
+		return err
+	}
+	if in.GetOffset() > size {
+		return status.Errorf(codes.OutOfRange, "Offset %d is past the end of the %d byte file", in.GetOffset(), size)
+	}
+
+	// Send the chunks overlapping the range. An empty range at the end of
+	// the file still gets the last chunk, so the response can be verified.
+	end := size
+	if length := in.GetLength(); length > 0 && length < size-in.GetOffset() {
+		end = in.GetOffset() + length
+	}
+	numChunks := merkleTree.NumChunks(size)
+	firstChunk := int(in.GetOffset() / merkleTree.ChunkSize)
+	if firstChunk == numChunks {
+		firstChunk--
+	}
+	lastChunk := firstChunk
+	if end > in.GetOffset() {
+		lastChunk = int((end - 1) / merkleTree.ChunkSize)
+	}
+
+	// Only the chunks in the range are read, one at a time.
+	chunkTree, err := s.chunkTree(in.GetName(), size)
+	if err != nil {
+		return err
+	}
+	entry := chunkTree.Entry(in.GetName())
+	leafIndex, err := latestLeaf(in.GetName(), s.MerkleTree.GetIndicesFromContent(entry.Encode()))
+	if err != nil {
+		return err
+	}
+	fileProof, root, err := s.MerkleTree.GenerateProofWithRoot(leafIndex)
+	if err != nil {
+		log.Printf("Error generating Merkle proof: %v", err)
+		return status.Errorf(codes.Internal, "Could not generate Merkle proof")
+	}
+
+	msg := &pb.FileChunk{
+		Entry: &pb.FileEntry{
+			Name:        entry.Name,
+			Size:        entry.Size,
+			ContentHash: entry.ContentHash,
+		},
+		FileProof: proofToPB(fileProof),
+		TreeHead:  treeHeadToPB(root, fileProof.TreeSize),
+	}
+	r := s.fileReader(in.GetName(), uint64(firstChunk)*merkleTree.ChunkSize, size)
+	buf := make([]byte, merkleTree.ChunkSize)
+	for i := firstChunk; i <= lastChunk; i++ {
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && !(err == io.EOF && size == 0) {
+			log.Printf("Failed to read chunk %d of %s: %v", i, in.GetName(), err)
+			return status.Errorf(codes.Internal, "Internal Server Error")
+		}
+		chunkProof, err := chunkTree.GenerateProof(i)
+		if err != nil {
+			log.Printf("Error generating chunk proof: %v", err)
+			return status.Errorf(codes.Internal, "Could not generate chunk proof")
+		}
+		msg.Chunk = buf[:n]
+		msg.ChunkProof = proofToPB(chunkProof)
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+		msg = &pb.FileChunk{}
+	}
+	return nil
+}
+
+func (s *FileTransferServer) DownloadFiles(ctx context.Context, in *pb.FileNames) (*pb.BatchDownloadResponse, error) {
+	log.Printf("Received DownloadFiles request for files: %v\n", in.GetNames())
+	mt, err := s.fullTree()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*pb.FileData
+	var leafIndices []int
+	for _, name := range in.GetNames() {
+		fileContent, err := s.fetchFile(name)
+		if err != nil {
+			return nil, err
+		}
+		leafIndex, err := s.leafIndex(name, fileContent)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &pb.FileData{Name: name, Content: fileContent})
+		leafIndices = append(leafIndices, leafIndex)
+	}
+
+	proof, root, err := mt.GenerateMultiProofWithRoot(leafIndices)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Could not generate Merkle multiproof: %v", err)
+	}
+
+	pbProof := &pb.MultiProof{
+		TreeSize:  uint64(proof.TreeSize),
+		Hashes:    proof.Hashes,
+		HashMode:  pb.HashMode(proof.Mode),
+		Algorithm: string(proof.Algorithm),
+		Shape:     pb.Shape(proof.Shape),
+	}
+	for _, i := range proof.Indices {
+		pbProof.LeafIndices = append(pbProof.LeafIndices, uint64(i))
+	}
+	return &pb.BatchDownloadResponse{
+		Files:       files,
+		MerkleProof: pbProof,
+		TreeHead:    treeHeadToPB(root, proof.TreeSize),
+	}, nil
+}
+
+func (s *FileTransferServer) GetConsistencyProof(ctx context.Context, in *pb.ConsistencyRequest) (*pb.ConsistencyResponse, error) {
+	log.Printf("Received GetConsistencyProof request from size %d to %d\n", in.GetOldSize(), in.GetNewSize())
+	mt, err := s.fullTree()
+	if err != nil {
+		return nil, err
+	}
+
+	newSize := int(in.GetNewSize())
+	if newSize == 0 {
+		newSize = mt.Size()
+	}
+
+	proof, err := mt.ConsistencyProof(int(in.GetOldSize()), newSize)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Could not generate consistency proof: %v", err)
+	}
+	root, err := mt.RootAt(newSize)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Could not compute root: %v", err)
+	}
+
+	return &pb.ConsistencyResponse{
+		Proof: &pb.ConsistencyProof{
+			OldSize:   uint64(proof.OldSize),
+			NewSize:   uint64(proof.NewSize),
+			Hashes:    proof.Hashes,
+			HashMode:  pb.HashMode(proof.Mode),
+			Algorithm: string(proof.Algorithm),
+			Shape:     pb.Shape(proof.Shape),
+		},
+		NewHead: treeHeadToPB(root, newSize),
+	}, nil
+}
+
+func (s *FileTransferServer) GetTreeState(ctx context.Context, in *pb.TreeStateRequest) (*pb.TreeState, error) {
+	log.Printf("Received GetTreeState request\n")
+	if !s.Debug {
+		return nil, status.Errorf(codes.PermissionDenied, "GetTreeState is disabled, set DEBUG_RPCS to enable it")
+	}
+	mt, err := s.fullTree()
+	if err != nil {
+		return nil, err
+	}
+
+	state := mt.State()
+	return &pb.TreeState{
+		Version:   uint32(state.Version),
+		Algorithm: string(state.Algorithm),
+		HashMode:  pb.HashMode(state.Mode),
+		Shape:     pb.Shape(state.Shape),
+		TreeSize:  uint64(len(state.Leaves)),
+		Leaves:    state.Leaves,
+		RootHash:  state.RootHash,
+	}, nil
+}
+
+// Page sizes of ListFiles.
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// listOrders maps every ListFiles order to the SQL expression it sorts by.
+var listOrders = map[pb.ListOrder]string{
+	pb.ListOrder_LIST_ORDER_NAME:        "file_name",
+	pb.ListOrder_LIST_ORDER_UPLOAD_TIME: "uploaded_at",
+	pb.ListOrder_LIST_ORDER_SIZE:        "octet_length(file_content)",
+}
+
+// pageToken is the position after which the next page of ListFiles starts:
+// the sort key and name of the last file listed. It also records the request
+// it continues, so that a token cannot be reused with another listing.
+type pageToken struct {
+	Prefix     string       `json:"prefix"`
+	Order      pb.ListOrder `json:"order"`
+	Descending bool         `json:"descending"`
+	Key        string       `json:"key"`
+	Name       string       `json:"name"`
+}
+
+func (t pageToken) encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string) (pageToken, error) {
+	var t pageToken
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return t, err
+	}
+	return t, json.Unmarshal(data, &t)
+}
+
+// likePrefix returns a LIKE pattern matching the strings that start with
+// prefix.
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
+func (s *FileTransferServer) ListFiles(ctx context.Context, in *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	log.Printf("Received ListFiles request for prefix %q\n", in.GetPrefix())
+
+	keyExpr, ok := listOrders[in.GetOrder()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown order %v", in.GetOrder())
+	}
+	pageSize := int(in.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	direction, after := "ASC", ">"
+	if in.GetDescending() {
+		direction, after = "DESC", "<"
+	}
+
+	// Pages are delimited by the last (key, name) listed rather than by an
+	// offset, so that uploads between two pages neither repeat nor skip files.
+	query := "SELECT file_name, octet_length(file_content), uploaded_at FROM file_storage WHERE file_name LIKE $1"
+	args := []interface{}{likePrefix(in.GetPrefix())}
+	if in.GetPageToken() != "" {
+		token, err := decodePageToken(in.GetPageToken())
+		if err != nil || token.Prefix != in.GetPrefix() || token.Order != in.GetOrder() || token.Descending != in.GetDescending() {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid page token")
+		}
+		query += fmt.Sprintf(" AND (%s, file_name) %s ($2, $3)", keyExpr, after)
+		args = append(args, token.Key, token.Name)
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, file_name %s LIMIT %d", keyExpr, direction, direction, pageSize+1)
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Failed to list files: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	defer rows.Close()
+
+	response := &pb.ListFilesResponse{}
+	var next pageToken
+	for rows.Next() {
+		var name string
+		var size int64
+		var uploadedAt time.Time
+		if err := rows.Scan(&name, &size, &uploadedAt); err != nil {
+			log.Printf("Failed to list files: %v", err)
+			return nil, status.Errorf(codes.Internal, "Internal Server Error")
+		}
+		if len(response.Files) == pageSize {
+			response.NextPageToken = next.encode()
+			break
+		}
+
+		info := &pb.FileInfo{Name: name, Size: uint64(size), LeafIndex: -1, UploadTime: uploadedAt.Unix()}
+		if encoded, ok := s.Names.Get(name); ok {
+			entry, _ := merkleTree.DecodeEntry(encoded)
+			if leafIndices := s.MerkleTree.GetIndicesFromContent(encoded); len(leafIndices) > 0 {
+				info.ContentHash = entry.ContentHash
+				info.LeafIndex = int64(leafIndices[len(leafIndices)-1])
+			}
+		}
+		response.Files = append(response.Files, info)
+
+		next = pageToken{Prefix: in.GetPrefix(), Order: in.GetOrder(), Descending: in.GetDescending(), Key: name, Name: name}
+		switch in.GetOrder() {
+		case pb.ListOrder_LIST_ORDER_UPLOAD_TIME:
+			next.Key = uploadedAt.Format(time.RFC3339Nano)
+		case pb.ListOrder_LIST_ORDER_SIZE:
+			next.Key = strconv.FormatInt(size, 10)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Failed to list files: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	return response, nil
+}
+
+func (s *FileTransferServer) GetSubtreeHashes(ctx context.Context, in *pb.SubtreeHashesRequest) (*pb.SubtreeHashesResponse, error) {
+	mt, err := s.fullTree()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the size first, so that every subtree the peer asks for is inside
+	// it. A sync can rewrite leaves while the peer walks the tree; the peer
+	// then diffs against a mix of versions, and whatever it misses is found
+	// by its next sync.
+	size := mt.Size()
+	subtrees := make([]merkleTree.Subtree, len(in.GetSubtrees()))
+	for i, st := range in.GetSubtrees() {
+		subtrees[i] = merkleTree.Subtree{Height: int(st.GetHeight()), Index: int(st.GetIndex())}
+	}
+	hashes, err := mt.SubtreeHashes(subtrees)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Could not hash subtrees: %v", err)
+	}
+
+	return &pb.SubtreeHashesResponse{
+		TreeSize:  uint64(size),
+		Hashes:    hashes,
+		HashMode:  pb.HashMode(mt.Mode()),
+		Algorithm: string(mt.Algorithm()),
+	}, nil
+}
+
+func (s *FileTransferServer) GetLeafEntries(ctx context.Context, in *pb.LeafRange) (*pb.LeafEntries, error) {
+	log.Printf("Received GetLeafEntries request for leaves %d to %d\n", in.GetStart(), in.GetEnd())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if in.GetStart() > in.GetEnd() || in.GetEnd() > uint64(len(s.entries)) {
+		return nil, status.Errorf(codes.OutOfRange, "Leaves %d to %d out of range", in.GetStart(), in.GetEnd())
+	}
+	return &pb.LeafEntries{Entries: s.entries[in.GetStart():in.GetEnd()]}, nil
+}
+
+func (s *FileTransferServer) DumpTree(ctx context.Context, in *pb.DumpTreeRequest) (*pb.TreeDump, error) {
+	log.Printf("Received DumpTree request for tree size %d and file: %s\n", in.GetTreeSize(), in.GetName())
+	if !s.Debug {
+		return nil, status.Errorf(codes.PermissionDenied, "DumpTree is disabled, set DEBUG_RPCS to enable it")
+	}
+	mt, err := s.fullTree()
+	if err != nil {
+		return nil, err
+	}
+
+	treeSize := int(in.GetTreeSize())
+	if treeSize == 0 {
+		treeSize = mt.Size()
+	}
+	snapshot, err := mt.SnapshotAt(treeSize)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Could not find tree: %v", err)
+	}
+
+	var proof *merkleTree.Proof
+	if in.GetName() != "" {
+		fileContent, err := s.fetchFile(in.GetName())
+		if err != nil {
+			return nil, err
+		}
+		// Highlight the most recent leaf of the file in that version
+		leafIndex := -1
+		for _, i := range s.MerkleTree.GetIndicesFromEntry(in.GetName(), fileContent) {
+			if i < treeSize {
+				leafIndex = i
+			}
+		}
+		if leafIndex < 0 {
+			return nil, status.Errorf(codes.NotFound, "File not found in Merkle Tree of size %d", treeSize)
+		}
+		proof, err = snapshot.GenerateProof(leafIndex)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "Could not generate Merkle proof: %v", err)
+		}
+	}
+
+	dump, err := snapshot.Dump(proof)
+	if err != nil {
+		log.Printf("Error dumping tree: %v", err)
+		return nil, status.Errorf(codes.Internal, "Could not dump tree")
+	}
+	var buf bytes.Buffer
+	switch in.GetFormat() {
+	case pb.DumpFormat_DUMP_FORMAT_DOT:
+		err = dump.WriteDOT(&buf)
+	default:
+		err = dump.WriteJSON(&buf)
+	}
+	if err != nil {
+		log.Printf("Error writing tree dump: %v", err)
+		return nil, status.Errorf(codes.Internal, "Could not dump tree")
+	}
+	return &pb.TreeDump{Format: in.GetFormat(), Data: buf.Bytes()}, nil
+}
+
+// addEntry appends a valid encoded entry to the trees and returns its leaf
+// index. s.mu must be held.
+func (s *FileTransferServer) addEntry(encoded []byte) int {
+	entry, _ := merkleTree.DecodeEntry(encoded)
+	s.MerkleTree.AddFile(encoded)
+	s.Names.Set(entry.Name, encoded)
+	s.entries = append(s.entries, encoded)
+	return len(s.entries) - 1
+}
+
+// syncLoop repairs the tree from the peer at addr every interval, plus a
+// random delay so that two peers syncing from each other rarely repair at
+// the same time.
+func (s *FileTransferServer) syncLoop(addr string, interval time.Duration) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to peer %s: %v", addr, err)
+	}
+	defer conn.Close()
+	peer := pb.NewFileTransferClient(conn)
+
+	for {
+		time.Sleep(interval + time.Duration(mathrand.Int63n(int64(interval))))
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		if err := s.syncFrom(ctx, peer); err != nil {
+			log.Printf("Failed to sync with peer %s: %v", addr, err)
+		}
+		cancel()
+	}
+}
+
+// syncFrom diffs the tree against the peer's and copies the peer's leaves
+// wherever the two differ. The entries this displaces are appended again
+// unless the tree already holds them, so two peers syncing from each other
+// end up with the same leaves in the same order. Replacing leaves rewrites
+// history: roots published before a repair are not consistent with the
+// roots after it.
+func (s *FileTransferServer) syncFrom(ctx context.Context, peer pb.FileTransferClient) error {
+	mt, err := s.fullTree()
+	if err != nil {
+		return err
+	}
+
+	head, err := peer.GetSubtreeHashes(ctx, &pb.SubtreeHashesRequest{})
+	if err != nil {
+		return err
+	}
+	if merkleTree.Algorithm(head.GetAlgorithm()) != mt.Algorithm() || merkleTree.HashMode(head.GetHashMode()) != mt.Mode() {
+		return status.Errorf(codes.FailedPrecondition, "Peer uses %s in mode %v", head.GetAlgorithm(), head.GetHashMode())
+	}
+	peerSize := int(head.GetTreeSize())
+
+	ranges, err := merkleTree.DiffRemote(mt, peerSize, func(subtrees []merkleTree.Subtree) ([][]byte, error) {
+		req := &pb.SubtreeHashesRequest{}
+		for _, st := range subtrees {
+			req.Subtrees = append(req.Subtrees, &pb.Subtree{Height: uint32(st.Height), Index: uint64(st.Index)})
+		}
+		resp, err := peer.GetSubtreeHashes(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetHashes(), nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	log.Printf("Tree differs from the peer at leaves %v", ranges)
+
+	// Fetch every entry before changing anything, so that neither a failed
+	// call nor a peer syncing from us at the same time can catch the repair
+	// half done or waiting on s.mu.
+	repairs := make(map[int][]byte)
+	for _, r := range ranges {
+		// Leaves past the end of the peer's tree are ours alone.
+		if r.Start >= peerSize {
+			continue
+		}
+		if r.End > peerSize {
+			r.End = peerSize
+		}
+		resp, err := peer.GetLeafEntries(ctx, &pb.LeafRange{Start: uint64(r.Start), End: uint64(r.End)})
+		if err != nil {
+			return err
+		}
+		if len(resp.GetEntries()) != r.End-r.Start {
+			return status.Errorf(codes.DataLoss, "Peer sent %d entries for leaves %d to %d", len(resp.GetEntries()), r.Start, r.End)
+		}
+		for i, encoded := range resp.GetEntries() {
+			if _, err := merkleTree.DecodeEntry(encoded); err != nil {
+				return status.Errorf(codes.DataLoss, "Peer sent an invalid entry for leaf %d: %v", r.Start+i, err)
+			}
+			repairs[r.Start+i] = encoded
+		}
+	}
+
+	// Apply the whole repair at once, in leaf order.
+	indices := make([]int, 0, len(repairs))
+	for index := range repairs {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var displaced [][]byte
+	for _, index := range indices {
+		encoded := repairs[index]
+		// The tree may have grown since the diff, but only ever grows.
+		if index >= len(s.entries) {
+			s.addEntry(encoded)
+			continue
+		}
+		if bytes.Equal(s.entries[index], encoded) {
+			continue
+		}
+		if err := mt.UpdateLeaf(index, encoded); err != nil {
+			log.Printf("Failed to repair leaf %d: %v", index, err)
+			continue
+		}
+		entry, _ := merkleTree.DecodeEntry(encoded)
+		displaced = append(displaced, s.entries[index])
+		s.entries[index] = encoded
+		s.Names.Set(entry.Name, encoded)
+	}
+
+	for _, encoded := range displaced {
+		if len(mt.GetIndicesFromContent(encoded)) == 0 {
+			s.addEntry(encoded)
+		}
+	}
+	log.Printf("Replaced %d leaves with the peer's", len(displaced))
+	return nil
+}
+
+// syncInterval returns the time between two syncs with the peer, set through
+// SYNC_INTERVAL.
+func syncInterval() time.Duration {
+	value, ok := os.LookupEnv("SYNC_INTERVAL")
+	if !ok {
+		return 30 * time.Second
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Fatalf("Invalid sync interval: %s", value)
+	}
+	return interval
+}
+
+// fetchFile reads the content of a stored file.
+func (s *FileTransferServer) fetchFile(name string) ([]byte, error) {
 	// Prepare SQL statement to fetch file content and metadata
 	stmt, err := s.DB.Prepare("SELECT file_content FROM file_storage WHERE file_name=$1")
 	if err != nil {
@@ -59,37 +1175,213 @@ func (s *FileTransferServer) DownloadFile(ctx context.Context, in *pb.FileName)
 	defer stmt.Close()
 
 	var fileContent []byte
-	err = stmt.QueryRow(in.GetName()).Scan(&fileContent)
+	err = stmt.QueryRow(name).Scan(&fileContent)
 	if err != nil {
 		log.Printf("Failed to execute SQL statement: %v", err)
-		return nil, status.Errorf(codes.NotFound, "File not found")
+		return nil, s.notFound(name)
 	}
 
-	log.Printf("Retrieved content: %x", fileContent)
+	log.Printf("Retrieved %d bytes of %s", len(fileContent), name)
 	if fileContent == nil {
-		return nil, status.Errorf(codes.NotFound, "File not found")
+		return nil, s.notFound(name)
+	}
+	return fileContent, nil
+}
+
+// fileSize returns the size of a stored file without reading it.
+func (s *FileTransferServer) fileSize(name string) (uint64, error) {
+	var size int64
+	err := s.DB.QueryRow("SELECT octet_length(file_content) FROM file_storage WHERE file_name=$1", name).Scan(&size)
+	if err == sql.ErrNoRows {
+		return 0, s.notFound(name)
+	}
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return 0, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	return uint64(size), nil
+}
+
+// chunkTree returns the chunk tree of a stored file of the given size. It is
+// rebuilt from the chunk hashes saved with the file, so that proving a chunk
+// does not read the whole file; files stored without them are hashed once
+// and get them saved.
+func (s *FileTransferServer) chunkTree(name string, size uint64) (*merkleTree.ChunkTree, error) {
+	var joined []byte
+	err := s.DB.QueryRow("SELECT chunk_hashes FROM file_storage WHERE file_name=$1", name).Scan(&joined)
+	if err == sql.ErrNoRows {
+		return nil, s.notFound(name)
+	}
+	if err != nil {
+		log.Printf("Failed to execute SQL statement: %v", err)
+		return nil, status.Errorf(codes.Internal, "Internal Server Error")
+	}
+	if hashes := splitHashes(joined, merkleTree.NumChunks(size)); hashes != nil {
+		chunkTree, err := merkleTree.NewChunkTreeFromHashes(size, hashes, merkleOptions()...)
+		if err == nil {
+			return chunkTree, nil
+		}
+		log.Printf("Ignoring the saved chunk hashes of %s: %v", name, err)
+	}
+
+	chunkTree, err := merkleTree.NewChunkTree(s.fileReader(name, 0, size), merkleOptions()...)
+	if err != nil {
+		log.Printf("Error building chunk tree: %v", err)
+		return nil, status.Errorf(codes.Internal, "Could not build chunk tree")
+	}
+	_, err = s.DB.Exec("UPDATE file_storage SET chunk_hashes=$2 WHERE file_name=$1", name, bytes.Join(chunkTree.ChunkHashes(), nil))
+	if err != nil {
+		log.Printf("Failed to save the chunk hashes of %s: %v", name, err)
+	}
+	return chunkTree, nil
+}
+
+// splitHashes splits n concatenated hashes of equal size, or returns nil if
+// joined cannot hold them.
+func splitHashes(joined []byte, n int) [][]byte {
+	if n == 0 || len(joined) == 0 || len(joined)%n != 0 {
+		return nil
+	}
+	size := len(joined) / n
+	hashes := make([][]byte, n)
+	for i := range hashes {
+		hashes[i] = joined[i*size : (i+1)*size]
+	}
+	return hashes
+}
+
+// fileReader returns a reader over a stored file of the given size, starting
+// at offset, that fetches at most one chunk per query.
+func (s *FileTransferServer) fileReader(name string, offset, size uint64) io.Reader {
+	return &dbFileReader{db: s.DB, name: name, offset: offset, size: size}
+}
+
+// dbFileReader reads a stored file piece by piece. init.sql stores
+// file_content uncompressed, so each piece costs the same wherever it lies.
+type dbFileReader struct {
+	db     *sql.DB
+	name   string
+	offset uint64
+	size   uint64
+}
+
+func (r *dbFileReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	n := uint64(len(p))
+	if n > merkleTree.ChunkSize {
+		n = merkleTree.ChunkSize
+	}
+	if n > r.size-r.offset {
+		n = r.size - r.offset
 	}
 
-	leafIndex := s.MerkleTree.GetIndexFromContent(fileContent)
-	if leafIndex == -1 {
-		return nil, status.Errorf(codes.NotFound, "File not found in Merkle Tree")
+	// SQL substrings count from 1
+	var data []byte
+	err := r.db.QueryRow("SELECT substring(file_content FROM $2 FOR $3) FROM file_storage WHERE file_name=$1",
+		r.name, int64(r.offset+1), int64(n)).Scan(&data)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	copied := copy(p, data)
+	r.offset += uint64(copied)
+	return copied, nil
+}
+
+// notFound returns a NotFound status carrying a proof that name is absent from
+// the name index. No proof is attached if the index does hold the name.
+func (s *FileTransferServer) notFound(name string) error {
+	st := status.New(codes.NotFound, "File not found")
+	if _, ok := s.Names.Get(name); ok {
+		return st.Err()
 	}
 
-	proof, err := s.MerkleTree.GenerateProof(leafIndex)
+	proof := s.Names.GenerateProof(name)
+	withProof, err := st.WithDetails(&pb.SparseProof{
+		Key:       proof.Key,
+		Value:     proof.Value,
+		Bitmap:    proof.Bitmap,
+		Siblings:  proof.Siblings,
+		HashMode:  pb.HashMode(proof.Mode),
+		Algorithm: string(proof.Algorithm),
+	})
+	if err != nil {
+		log.Printf("Failed to attach absence proof: %v", err)
+		return st.Err()
+	}
+	return withProof.Err()
+}
+
+// leafIndex returns the position of a stored file's entry in the Merkle tree.
+func (s *FileTransferServer) leafIndex(name string, fileContent []byte) (int, error) {
+	return latestLeaf(name, s.MerkleTree.GetIndicesFromEntry(name, fileContent))
+}
+
+// latestLeaf picks the leaf to prove among the leaves holding a file.
+func latestLeaf(name string, leafIndices []int) (int, error) {
+	if len(leafIndices) == 0 {
+		return 0, status.Errorf(codes.NotFound, "File not found in Merkle Tree")
+	}
+	// Every matching leaf proves the same file; use the most recent one.
+	leafIndex := leafIndices[len(leafIndices)-1]
+	if len(leafIndices) > 1 {
+		log.Printf("Content of %s matches leaves %v, proving leaf %d", name, leafIndices, leafIndex)
+	}
+	return leafIndex, nil
+}
+
+// fileProof returns the inclusion proof of a stored file's entry and the root
+// it verifies against, both read from the same version of the tree.
+func (s *FileTransferServer) fileProof(name string, fileContent []byte) (*merkleTree.Proof, merkleTree.Digest, error) {
+	leafIndex, err := s.leafIndex(name, fileContent)
+	if err != nil {
+		return nil, merkleTree.Digest{}, err
+	}
+
+	proof, root, err := s.MerkleTree.GenerateProofWithRoot(leafIndex)
 	if err != nil {
 		log.Printf("Error generating Merkle proof: %v", err)
-		return nil, status.Errorf(codes.Internal, "Could not generate Merkle proof")
+		return nil, merkleTree.Digest{}, status.Errorf(codes.Internal, "Could not generate Merkle proof")
 	}
-	return &pb.FileDownloadResponse{
-		Content:     fileContent,
-		MerkleProof: proof,
-	}, nil
+	return proof, root, nil
+}
+
+// proofToPB converts a Merkle proof to its wire representation.
+func proofToPB(proof *merkleTree.Proof) *pb.MerkleProof {
+	return &pb.MerkleProof{
+		LeafIndex: uint64(proof.LeafIndex),
+		TreeSize:  uint64(proof.TreeSize),
+		Siblings:  proof.Siblings,
+		Left:      proof.Left,
+		HashMode:  pb.HashMode(proof.Mode),
+		Algorithm: string(proof.Algorithm),
+		Shape:     pb.Shape(proof.Shape),
+	}
+}
 
+// treeHeadToPB converts a Merkle root of the given tree size to its wire
+// representation.
+func treeHeadToPB(root merkleTree.Digest, size int) *pb.TreeHead {
+	return &pb.TreeHead{
+		TreeSize:  uint64(size),
+		RootHash:  root.Hash,
+		HashMode:  pb.HashMode(root.Mode),
+		Algorithm: string(root.Algorithm),
+		Shape:     pb.Shape(root.Shape),
+	}
 }
+
 func NewFileTransferServer(db *sql.DB) *FileTransferServer {
 	return &FileTransferServer{
-		MerkleTree: merkleTree.NewMerkleTree(),
+		MerkleTree: newTree(),
+		Names:      merkleTree.NewSparseMerkleTree(merkleOptions()...),
 		DB:         db,
+		SessionTTL: sessionTTL(),
+		Debug:      debugRPCs(),
 	}
 }
 
@@ -111,6 +1403,14 @@ func main() {
 	fileTransferServer := NewFileTransferServer(db)
 	pb.RegisterFileTransferServer(grpcServer, fileTransferServer)
 
+	// Drop the upload sessions that were abandoned
+	go fileTransferServer.collectUploads(fileTransferServer.SessionTTL / 4)
+
+	// Keep the tree in step with a peer sharing the same database
+	if peer, ok := os.LookupEnv("SYNC_PEER"); ok {
+		go fileTransferServer.syncLoop(peer, syncInterval())
+	}
+
 	// Start listening
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {